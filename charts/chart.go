@@ -0,0 +1,285 @@
+// Package charts gera gráficos de preço em PNG usando apenas a biblioteca padrão do Go,
+// sem CGO nem bibliotecas gráficas do sistema (funciona em containers headless).
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"time"
+)
+
+// Point é um preço em um instante (gráfico de linha)
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Candle é um período OHLC (gráfico de velas)
+type Candle struct {
+	Time                   time.Time
+	Open, High, Low, Close float64
+}
+
+// Options controla títulos, tamanho e a formatação dos valores do eixo Y
+type Options struct {
+	Title       string // ex: BTC/BRL 7D
+	Subtitle    string // ex: R$ 350.000,00  +2,31%
+	Width       int
+	Height      int
+	FormatValue func(float64) string // rótulos do eixo Y e dos marcadores de máxima/mínima
+}
+
+var (
+	colorBackground = color.RGBA{255, 255, 255, 255}
+	colorText       = color.RGBA{31, 41, 55, 255}
+	colorMuted      = color.RGBA{107, 114, 128, 255}
+	colorGrid       = color.RGBA{229, 231, 235, 255}
+	colorUp         = color.RGBA{22, 163, 74, 255}
+	colorDown       = color.RGBA{220, 38, 38, 255}
+	colorUpFill     = color.NRGBA{22, 163, 74, 40}
+	colorDownFill   = color.NRGBA{220, 38, 38, 40}
+)
+
+const (
+	labelScale = 2
+	titleScale = 3
+)
+
+// Line desenha o gráfico de linha com área preenchida e marcadores de máxima e mínima
+func Line(points []Point, opt Options) ([]byte, error) {
+	if len(points) < 2 {
+		return nil, fmt.Errorf("dados insuficientes para o gráfico (%d pontos)", len(points))
+	}
+
+	lo, hi := points[0].Value, points[0].Value
+	iMin, iMax := 0, 0
+	for i, p := range points {
+		if p.Value < lo {
+			lo, iMin = p.Value, i
+		}
+		if p.Value > hi {
+			hi, iMax = p.Value, i
+		}
+	}
+
+	up := points[len(points)-1].Value >= points[0].Value
+	c := newCanvas(opt, points[0].Time, points[len(points)-1].Time, lo, hi, up)
+
+	stroke, fill := colorDown, color.Color(colorDownFill)
+	if up {
+		stroke, fill = colorUp, colorUpFill
+	}
+
+	// Área sob a curva, coluna a coluna (cada coluna uma única vez, senão a transparência acumula)
+	filled := c.plot.Min.X - 1
+	for i := 1; i < len(points); i++ {
+		x0, y0 := c.x(points[i-1].Time), c.y(points[i-1].Value)
+		x1, y1 := c.x(points[i].Time), c.y(points[i].Value)
+		for x := max(x0, filled+1); x <= x1; x++ {
+			y := y0
+			if x1 != x0 {
+				y = y0 + (y1-y0)*(x-x0)/(x1-x0)
+			}
+			blendRect(c.img, image.Rect(x, y, x+1, c.plot.Max.Y), fill)
+			filled = x
+		}
+	}
+	for i := 1; i < len(points); i++ {
+		drawLine(c.img, c.x(points[i-1].Time), c.y(points[i-1].Value), c.x(points[i].Time), c.y(points[i].Value), stroke, 3)
+	}
+
+	c.marker(c.x(points[iMax].Time), c.y(hi), "MAX "+c.format(hi), true)
+	c.marker(c.x(points[iMin].Time), c.y(lo), "MIN "+c.format(lo), false)
+
+	return c.encode()
+}
+
+// Candles desenha o gráfico de velas (OHLC) com marcadores de máxima e mínima
+func Candles(candles []Candle, opt Options) ([]byte, error) {
+	if len(candles) < 2 {
+		return nil, fmt.Errorf("dados insuficientes para o gráfico (%d velas)", len(candles))
+	}
+
+	lo, hi := candles[0].Low, candles[0].High
+	iMin, iMax := 0, 0
+	for i, k := range candles {
+		if k.Low < lo {
+			lo, iMin = k.Low, i
+		}
+		if k.High > hi {
+			hi, iMax = k.High, i
+		}
+	}
+
+	up := candles[len(candles)-1].Close >= candles[0].Open
+	c := newCanvas(opt, candles[0].Time, candles[len(candles)-1].Time, lo, hi, up)
+
+	body := int(float64(c.plot.Dx()) / float64(len(candles)) * 0.6)
+	if body < 1 {
+		body = 1
+	}
+
+	for _, k := range candles {
+		col := colorUp
+		if k.Close < k.Open {
+			col = colorDown
+		}
+		x := c.x(k.Time)
+		drawLine(c.img, x, c.y(k.High), x, c.y(k.Low), col, 1)
+
+		top, bottom := c.y(math.Max(k.Open, k.Close)), c.y(math.Min(k.Open, k.Close))
+		if bottom-top < 1 {
+			bottom = top + 1
+		}
+		fillRect(c.img, image.Rect(x-body/2, top, x-body/2+body, bottom), col)
+	}
+
+	c.marker(c.x(candles[iMax].Time), c.y(hi), "MAX "+c.format(hi), true)
+	c.marker(c.x(candles[iMin].Time), c.y(lo), "MIN "+c.format(lo), false)
+
+	return c.encode()
+}
+
+//
+// ========== 🖼️ Tela =========
+//
+
+// canvas guarda a imagem, a área de plotagem e as escalas de tempo e valor
+type canvas struct {
+	img    *image.RGBA
+	plot   image.Rectangle
+	opt    Options
+	t0, t1 time.Time
+	lo, hi float64
+}
+
+func newCanvas(opt Options, t0, t1 time.Time, lo, hi float64, up bool) *canvas {
+	if opt.Width <= 0 {
+		opt.Width = 900
+	}
+	if opt.Height <= 0 {
+		opt.Height = 500
+	}
+	if opt.FormatValue == nil {
+		opt.FormatValue = func(v float64) string { return fmt.Sprintf("%.2f", v) }
+	}
+
+	// Margem de 15% acima e abaixo para caber os rótulos de máxima e mínima
+	pad := (hi - lo) * 0.15
+	if pad == 0 {
+		pad = math.Max(math.Abs(hi)*0.01, 0.01)
+	}
+	c := &canvas{opt: opt, t0: t0, t1: t1, lo: lo - pad, hi: hi + pad}
+
+	c.img = image.NewRGBA(image.Rect(0, 0, opt.Width, opt.Height))
+	fillRect(c.img, c.img.Bounds(), colorBackground)
+
+	// Eixo Y: a margem esquerda acompanha o rótulo mais largo
+	const ticks = 5
+	labels := make([]string, ticks)
+	left := 0
+	for i := range labels {
+		labels[i] = c.format(c.lo + (c.hi-c.lo)*float64(i)/float64(ticks-1))
+		if w := textWidth(labels[i], labelScale); w > left {
+			left = w
+		}
+	}
+
+	top := 20 + textHeight(titleScale) + 12 + textHeight(labelScale) + 24
+	c.plot = image.Rect(left+30, top, opt.Width-30, opt.Height-50)
+
+	// Cabeçalho
+	drawText(c.img, 20, 20, opt.Title, colorText, titleScale)
+	sub := colorDown
+	if up {
+		sub = colorUp
+	}
+	drawText(c.img, 20, 20+textHeight(titleScale)+12, opt.Subtitle, sub, labelScale)
+
+	// Grade e rótulos do eixo Y
+	for i, label := range labels {
+		y := c.y(c.lo + (c.hi-c.lo)*float64(i)/float64(ticks-1))
+		drawDashedHLine(c.img, c.plot.Min.X, c.plot.Max.X, y, colorGrid)
+		drawText(c.img, c.plot.Min.X-10-textWidth(label, labelScale), y-textHeight(labelScale)/2, label, colorMuted, labelScale)
+	}
+
+	// Rótulos do eixo X
+	layout := timeLayout(t1.Sub(t0))
+	for i := 0; i < ticks; i++ {
+		t := t0.Add(time.Duration(float64(t1.Sub(t0)) * float64(i) / float64(ticks-1)))
+		label := t.Format(layout)
+		x := c.x(t) - textWidth(label, labelScale)/2
+		x = clamp(x, 0, opt.Width-textWidth(label, labelScale))
+		drawText(c.img, x, c.plot.Max.Y+16, label, colorMuted, labelScale)
+	}
+
+	fillRect(c.img, image.Rect(c.plot.Min.X, c.plot.Max.Y, c.plot.Max.X, c.plot.Max.Y+1), colorMuted)
+	return c
+}
+
+func (c *canvas) x(t time.Time) int {
+	span := c.t1.Sub(c.t0)
+	if span <= 0 {
+		return c.plot.Min.X
+	}
+	return c.plot.Min.X + int(float64(c.plot.Dx())*float64(t.Sub(c.t0))/float64(span))
+}
+
+func (c *canvas) y(v float64) int {
+	return c.plot.Max.Y - int(float64(c.plot.Dy())*(v-c.lo)/(c.hi-c.lo))
+}
+
+func (c *canvas) format(v float64) string {
+	return c.opt.FormatValue(v)
+}
+
+// marker destaca um ponto com rótulo acima (máxima) ou abaixo (mínima), sem sair da área do gráfico
+func (c *canvas) marker(x, y int, label string, above bool) {
+	drawDot(c.img, x, y, 5, colorText)
+
+	w, h := textWidth(label, labelScale), textHeight(labelScale)
+	lx := clamp(x-w/2, c.plot.Min.X, c.plot.Max.X-w)
+	ly := y + 12
+	if above {
+		ly = y - 12 - h
+	}
+	ly = clamp(ly, c.plot.Min.Y+4, c.plot.Max.Y-h-6)
+
+	bg := image.Rect(lx-4, ly-4, lx+w+4, ly+h+4)
+	fillRect(c.img, bg, colorBackground)
+	drawText(c.img, lx, ly, label, colorText, labelScale)
+}
+
+func (c *canvas) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, fmt.Errorf("erro ao gerar PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// timeLayout escolhe o formato das datas do eixo X conforme o período exibido
+func timeLayout(span time.Duration) string {
+	switch {
+	case span <= 48*time.Hour:
+		return "15:04"
+	case span <= 200*24*time.Hour:
+		return "02/01"
+	default:
+		return "01/06"
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
@@ -0,0 +1,81 @@
+package charts
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// fillRect pinta um retângulo sólido (recortado pelos limites da imagem)
+func fillRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r.Intersect(img.Bounds()), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+// blendRect pinta um retângulo com transparência sobre o que já foi desenhado
+func blendRect(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r.Intersect(img.Bounds()), &image.Uniform{C: c}, image.Point{}, draw.Over)
+}
+
+// drawLine traça uma reta (Bresenham) com a espessura informada
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color, thickness int) {
+	dx, sx := abs(x1-x0), sign(x1-x0)
+	dy, sy := -abs(y1-y0), sign(y1-y0)
+	err := dx + dy
+	half := thickness / 2
+
+	for {
+		fillRect(img, image.Rect(x0-half, y0-half, x0-half+thickness, y0-half+thickness), c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// drawDashedHLine traça uma linha horizontal tracejada (usada na grade)
+func drawDashedHLine(img *image.RGBA, x0, x1, y int, c color.Color) {
+	for x := x0; x < x1; x += 6 {
+		end := x + 3
+		if end > x1 {
+			end = x1
+		}
+		fillRect(img, image.Rect(x, y, end, y+1), c)
+	}
+}
+
+// drawDot desenha um marcador redondo de raio r centrado em (cx, cy)
+func drawDot(img *image.RGBA, cx, cy, r int, c color.Color) {
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if x*x+y*y <= r*r {
+				img.Set(cx+x, cy+y, c)
+			}
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
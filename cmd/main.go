@@ -13,9 +13,9 @@ import (
 	"github.com/faysk/whatsapp-bot/scheduler"
 	"github.com/faysk/whatsapp-bot/services"
 	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
 	"github.com/faysk/whatsapp-bot/utils"
 	"go.mau.fi/whatsmeow"
-	"go.mau.fi/whatsmeow/types"
 )
 
 func main() {
@@ -36,16 +36,19 @@ func main() {
 
 	log.Println("✅ Bot conectado com sucesso. Aguardando mensagens...")
 
-	scheduler.StartDailyNews(ctx, client, config.AppConfig.AuthorizedNumbers)
+	// 📡 Transporte: envios diretos para a fila de saída, respostas dos comandos via fila
+	raw := transport.NewWhatsApp(client)
+	services.StartOutbox(ctx, raw)
+	messenger := services.Queued(raw)
 
-	services.MonitorCryptos(func(msg string) {
-		for _, number := range config.AppConfig.AuthorizedNumbers {
-			jid := types.NewJID(number, "s.whatsapp.net")
-			services.SendReply(ctx, client, jid, msg)
-		}
-	})
+	services.StartDeferredDelivery(ctx, messenger)
+	services.StartCoinDirectory(ctx)
+
+	scheduler.StartDailyNews(ctx, messenger, config.AppConfig.AuthorizedNumbers)
+
+	services.MonitorCryptos(ctx, messenger)
 
-	events.Listen(ctx, client)
+	events.Listen(ctx, client, messenger)
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -60,6 +63,10 @@ func main() {
 func startBot(ctx context.Context) (*whatsmeow.Client, error) {
 	config.Load()
 
+	if err := services.LoadTemplates(); err != nil {
+		return nil, fmt.Errorf("erro ao carregar templates: %w", err)
+	}
+
 	dynamic := store.LoadAuthorizedNumbers()
 	config.AddDynamicAuthorizedNumbers(dynamic)
 
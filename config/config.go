@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -24,11 +25,32 @@ type Config struct {
 	EnableChatGPT      bool
 	BotName            string
 	Language           string
+	Timezone           string // fuso padrão das conversas (horário de silêncio), alterável com !silencio fuso
 	MaxTokens          int
 	Temperature        float64
 	RestrictToGroup    bool
 	FixedAuthorizedEnv []string
 	AuthorizedNumbers  []string
+	AdminNumbers       []string
+	TemplatesDir       string
+	TodosCooldown      time.Duration
+
+	OutboxGlobalRate  int // mensagens por minuto (todas as conversas)
+	OutboxChatRate    int // mensagens por minuto (por destinatário)
+	OutboxMaxAttempts int
+	OutboxPoll        time.Duration
+
+	PriceCacheTTL   time.Duration // validade das cotações em cache
+	CoinListRefresh time.Duration // intervalo de atualização do diretório de moedas
+	MarketProviders []string      // fontes de cotação em ordem de prioridade
+
+	QuoteProviders []string // fontes de ações, índices, câmbio e commodities em ordem de prioridade
+	BrapiToken     string
+	QuoteStubFile  string // cotações fixas para desenvolvimento (provedor "stub")
+
+	MonitoredCoins  []string      // moedas verificadas pelo monitor de ATH (alteráveis com !monitor)
+	MonitorInterval time.Duration // intervalo entre verificações do monitor
+	ATHRecordsFile  string        // antigo crypto_records.json, importado uma única vez para o banco
 }
 
 // AppConfig é a instância global acessada pelo projeto
@@ -59,16 +81,52 @@ func Load() {
 		OpenAIModel:        getEnv("OPENAI_MODEL", "gpt-4o"),
 		EnableChatGPT:      getBool("ENABLE_CHATGPT", true),
 		BotName:            getEnv("BOT_NAME", "FayskBot"),
-		Language:           getEnv("LANG", "pt-BR"),
+		Language:           getEnv("BOT_LANG", "pt-BR"),
+		Timezone:           getEnv("BOT_TIMEZONE", "America/Sao_Paulo"),
 		MaxTokens:          getInt("MAX_TOKENS", 400),
 		Temperature:        getFloat("TEMPERATURE", 0.7),
 		RestrictToGroup:    getBool("RESTRICT_TO_GROUP", false),
 		FixedAuthorizedEnv: parseCSVEnv("AUTHORIZED_NUMBERS"),
 		AuthorizedNumbers:  []string{},
+		TemplatesDir:       getEnv("TEMPLATES_DIR", "templates"),
+		TodosCooldown:      getDuration("TODOS_COOLDOWN", 10*time.Minute),
+
+		OutboxGlobalRate:  getInt("OUTBOX_GLOBAL_RATE", 30),
+		OutboxChatRate:    getInt("OUTBOX_CHAT_RATE", 10),
+		OutboxMaxAttempts: getInt("OUTBOX_MAX_ATTEMPTS", 5),
+		OutboxPoll:        getDuration("OUTBOX_POLL_INTERVAL", 2*time.Second),
+
+		PriceCacheTTL:   getDuration("PRICE_CACHE_TTL", 60*time.Second),
+		CoinListRefresh: getDuration("COIN_LIST_REFRESH", 24*time.Hour),
+		MarketProviders: parseCSVEnv("MARKET_PROVIDERS"),
+
+		QuoteProviders: parseCSVEnv("QUOTE_PROVIDERS"),
+		BrapiToken:     getEnv("BRAPI_TOKEN", ""),
+		QuoteStubFile:  getEnv("QUOTE_STUB_FILE", "quotes_stub.json"),
+
+		MonitoredCoins:  parseCSVEnv("MONITORED_COINS"),
+		MonitorInterval: getDuration("MONITOR_INTERVAL", 5*time.Minute),
+		ATHRecordsFile:  getEnv("ATH_RECORDS_FILE", "crypto_records.json"),
 	}
 
 	AppConfig.AuthorizedNumbers = append(AppConfig.AuthorizedNumbers, AppConfig.FixedAuthorizedEnv...)
 
+	if len(AppConfig.MarketProviders) == 0 {
+		AppConfig.MarketProviders = []string{"coingecko", "binance", "kraken", "coinbase"}
+	}
+	if len(AppConfig.QuoteProviders) == 0 {
+		AppConfig.QuoteProviders = []string{"yahoo", "brapi"}
+	}
+	if len(AppConfig.MonitoredCoins) == 0 {
+		AppConfig.MonitoredCoins = []string{"btc", "eth", "usdt", "xrp", "sol"}
+	}
+
+	// Sem ADMIN_NUMBERS, os números fixos do .env são os administradores
+	AppConfig.AdminNumbers = parseCSVEnv("ADMIN_NUMBERS")
+	if len(AppConfig.AdminNumbers) == 0 {
+		AppConfig.AdminNumbers = AppConfig.FixedAuthorizedEnv
+	}
+
 	if AppConfig.OpenAIKey == "" && AppConfig.EnableChatGPT {
 		log.Fatal("❌ OPENAI_API_KEY está ausente, mas IA está ativada. Verifique .env")
 	}
@@ -87,12 +145,23 @@ func printLoadedConfig() {
 	log.Printf("  ├─ LOG_LEVEL:          %s", AppConfig.LogLevel)
 	log.Printf("  ├─ PORT:               %s", AppConfig.Port)
 	log.Printf("  ├─ BOT_NAME:           %s", AppConfig.BotName)
-	log.Printf("  ├─ LANG:               %s", AppConfig.Language)
+	log.Printf("  ├─ BOT_LANG:           %s", AppConfig.Language)
+	log.Printf("  ├─ BOT_TIMEZONE:       %s", AppConfig.Timezone)
+	log.Printf("  ├─ TEMPLATES_DIR:      %s", AppConfig.TemplatesDir)
 	log.Printf("  ├─ OPENAI_MODEL:       %s", AppConfig.OpenAIModel)
 	log.Printf("  ├─ MAX_TOKENS:         %d", AppConfig.MaxTokens)
 	log.Printf("  ├─ TEMPERATURE:        %.2f", AppConfig.Temperature)
 	log.Printf("  ├─ RESTRICT_TO_GROUP:  %v", AppConfig.RestrictToGroup)
 	log.Printf("  ├─ FIXED NUMBERS:      %v", AppConfig.FixedAuthorizedEnv)
+	log.Printf("  ├─ ADMIN NUMBERS:      %v", AppConfig.AdminNumbers)
+	log.Printf("  ├─ OUTBOX RATE:        %d/min global, %d/min por conversa", AppConfig.OutboxGlobalRate, AppConfig.OutboxChatRate)
+	log.Printf("  ├─ PRICE_CACHE_TTL:    %s", AppConfig.PriceCacheTTL)
+	log.Printf("  ├─ COIN_LIST_REFRESH:  %s", AppConfig.CoinListRefresh)
+	log.Printf("  ├─ MARKET_PROVIDERS:   %v", AppConfig.MarketProviders)
+	log.Printf("  ├─ QUOTE_PROVIDERS:    %v", AppConfig.QuoteProviders)
+	log.Printf("  ├─ MONITORED_COINS:    %v", AppConfig.MonitoredCoins)
+	log.Printf("  ├─ MONITOR_INTERVAL:   %s", AppConfig.MonitorInterval)
+	log.Printf("  ├─ ATH_RECORDS_FILE:   %s", AppConfig.ATHRecordsFile)
 
 	if AppConfig.OpenAIKey != "" && AppConfig.EnableChatGPT {
 		log.Println("  └─ IA: ✅ habilitada (ChatGPT ativo)")
@@ -116,6 +185,11 @@ func AddDynamicAuthorizedNumbers(dynamic []string) {
 	}
 }
 
+// IsAdmin informa se o número pode executar comandos administrativos
+func IsAdmin(number string) bool {
+	return contains(AppConfig.AdminNumbers, number)
+}
+
 //
 // ========== 🧰 Utilitários =========
 //
@@ -145,6 +219,15 @@ func getFloat(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	if val := os.Getenv(key); val != "" {
+		if d, err := time.ParseDuration(val); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
 func getBool(key string, defaultValue bool) bool {
 	if val := os.Getenv(key); val != "" {
 		if b, err := strconv.ParseBool(val); err == nil {
@@ -5,12 +5,15 @@ import (
 	"log"
 
 	"github.com/faysk/whatsapp-bot/handlers"
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
 	"go.mau.fi/whatsmeow"
 	waEvents "go.mau.fi/whatsmeow/types/events"
 )
 
-// Listen registra os listeners de eventos no cliente WhatsApp
-func Listen(ctx context.Context, client *whatsmeow.Client) {
+// Listen registra os listeners de eventos no cliente WhatsApp.
+// As respostas dos comandos saem pelo Messenger informado (normalmente a fila de saída).
+func Listen(ctx context.Context, client *whatsmeow.Client, m transport.Messenger) {
 	client.AddEventHandler(func(evt interface{}) {
 		switch msg := evt.(type) {
 
@@ -27,7 +30,11 @@ func Listen(ctx context.Context, client *whatsmeow.Client) {
 			}
 
 			log.Printf("📨 [%s] %s", msg.Info.Sender.User, text)
-			handlers.HandleCommand(ctx, client, msg.Info.Chat, text, msg)
+			handlers.HandleCommand(ctx, transport.NewConversation(m, msg.Info.Chat, msg.Info.Sender, text, msg))
+
+		case *waEvents.Receipt:
+			// Confirmações de entrega/leitura das mensagens enviadas pelo bot
+			services.HandleReceipt(ctx, msg)
 
 		// Futuro: adicionar suporte a eventos como presença, status, etc.
 		default:
@@ -0,0 +1,66 @@
+// Package format formata números, valores monetários e percentuais conforme o idioma:
+// precisão por dígitos significativos para preços abaixo de um centavo, arredondamento correto,
+// negativos e notação compacta ("R$ 1,2 tri", "$3.4B").
+package format
+
+import "strings"
+
+// Locale reúne separadores, símbolos de moeda e sufixos da notação compacta de um idioma
+type Locale struct {
+	Thousands   string
+	Decimal     string
+	SymbolSpace bool              // "R$ 10,00" (pt-BR) ou "$10.00" (en)
+	Symbols     map[string]string // código ISO → símbolo
+	Compact     [4]string         // sufixos para mil, milhão, bilhão e trilhão
+}
+
+var (
+	// PtBR é o padrão brasileiro: 1.234,56 | R$ 1,2 tri
+	PtBR = Locale{
+		Thousands:   ".",
+		Decimal:     ",",
+		SymbolSpace: true,
+		Symbols:     map[string]string{"BRL": "R$", "USD": "US$", "EUR": "€", "GBP": "£", "JPY": "¥"},
+		Compact:     [4]string{" mil", " mi", " bi", " tri"},
+	}
+
+	// EnUS é o padrão americano: 1,234.56 | $3.4B
+	EnUS = Locale{
+		Thousands: ",",
+		Decimal:   ".",
+		Symbols:   map[string]string{"BRL": "R$", "USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥"},
+		Compact:   [4]string{"K", "M", "B", "T"},
+	}
+)
+
+// ForLanguage escolhe o Locale pelo idioma (pt-BR, en, en-US, en_US.UTF-8...); o padrão é PtBR
+func ForLanguage(lang string) Locale {
+	lang = strings.ToLower(strings.ReplaceAll(lang, "_", "-"))
+	if lang == "en" || strings.HasPrefix(lang, "en-") {
+		return EnUS
+	}
+	return PtBR
+}
+
+// Symbol devolve o símbolo da moeda (ou o próprio código, se desconhecida)
+func (l Locale) Symbol(currency string) string {
+	code := strings.ToUpper(currency)
+	if s, ok := l.Symbols[code]; ok {
+		return s
+	}
+	return code
+}
+
+// withSymbol põe o símbolo antes do número, com o sinal negativo na frente (-R$ 5,00)
+func (l Locale) withSymbol(num, currency string) string {
+	sign := ""
+	if strings.HasPrefix(num, "-") {
+		sign, num = "-", num[1:]
+	}
+
+	symbol := l.Symbol(currency)
+	if l.SymbolSpace || len(symbol) > 1 && symbol == strings.ToUpper(currency) {
+		return sign + symbol + " " + num
+	}
+	return sign + symbol + num
+}
@@ -0,0 +1,121 @@
+package format
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	significantDigits = 4  // precisão mínima de valores abaixo de 1 (0,00001234)
+	maxDecimals       = 12 // limite de casas para valores muito pequenos
+)
+
+// Number formata com 2 casas a partir de 1 e, abaixo disso, com dígitos significativos
+// suficientes para o valor não virar zero (SHIB: 0,00001234), sem zeros sobrando além da 2ª casa
+func (l Locale) Number(v float64) string {
+	return l.trimmed(v, decimalsFor(v), 2)
+}
+
+// Fixed formata com o número exato de casas, arredondando (não truncando)
+func (l Locale) Fixed(v float64, decimals int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "-"
+	}
+
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	intPart, frac, _ := strings.Cut(s, ".")
+
+	out := groupThousands(intPart, l.Thousands)
+	if frac != "" {
+		out += l.Decimal + frac
+	}
+	// Não exibe "-0,00" quando o arredondamento zera o valor
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		out = "-" + out
+	}
+	return out
+}
+
+// Money formata o valor com o símbolo da moeda (R$ 1.234,56 | $0.00001234 | -US$ 5,00)
+func (l Locale) Money(v float64, currency string) string {
+	return l.withSymbol(l.Number(v), currency)
+}
+
+// Percent formata percentuais com 2 casas (12,34%); com signed, positivos ganham "+"
+func (l Locale) Percent(v float64, signed bool) string {
+	s := l.Fixed(v, 2) + "%"
+	if signed && v > 0 && strings.Trim(s, "0.,%") != "" {
+		s = "+" + s
+	}
+	return s
+}
+
+// CompactNumber abrevia valores grandes (1,2 tri | 3.4B); abaixo de mil usa Number
+func (l Locale) CompactNumber(v float64) string {
+	abs := math.Abs(v)
+	for i := len(l.Compact) - 1; i >= 0; i-- {
+		unit := math.Pow(1000, float64(i+1))
+		if abs < unit*0.99995 { // 999,96 bi arredonda para 1 tri, não "1.000 bi"
+			continue
+		}
+		scaled := v / unit
+		decimals := 1
+		if math.Abs(scaled) >= 100 {
+			decimals = 0
+		}
+		return l.trimmed(scaled, decimals, 0) + l.Compact[i]
+	}
+	return l.Number(v)
+}
+
+// CompactMoney abrevia valores monetários grandes (R$ 1,2 tri | $3.4B)
+func (l Locale) CompactMoney(v float64, currency string) string {
+	return l.withSymbol(l.CompactNumber(v), currency)
+}
+
+// trimmed formata com até "decimals" casas e remove zeros à direita, mantendo pelo menos "keep" casas
+func (l Locale) trimmed(v float64, decimals, keep int) string {
+	s := l.Fixed(v, decimals)
+	intPart, frac, ok := strings.Cut(s, l.Decimal)
+	if !ok {
+		return s
+	}
+	for len(frac) > keep && strings.HasSuffix(frac, "0") {
+		frac = frac[:len(frac)-1]
+	}
+	if frac == "" {
+		return intPart
+	}
+	return intPart + l.Decimal + frac
+}
+
+// decimalsFor escolhe as casas decimais: 2 a partir de 1, senão o necessário para os dígitos significativos
+func decimalsFor(v float64) int {
+	abs := math.Abs(v)
+	if abs == 0 || abs >= 1 || math.IsNaN(abs) || math.IsInf(abs, 0) {
+		return 2
+	}
+	decimals := int(-math.Floor(math.Log10(abs))) + significantDigits - 1
+	return min(max(decimals, 2), maxDecimals)
+}
+
+// groupThousands aplica o separador de milhar na parte inteira (já sem sinal)
+func groupThousands(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
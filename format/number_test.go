@@ -0,0 +1,52 @@
+package format
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLocaleFormatting(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"número pt-BR", PtBR.Number(1234.5), "1.234,50"},
+		{"número en", EnUS.Number(1234.5), "1,234.50"},
+		{"milhões", PtBR.Number(1234567.891), "1.234.567,89"},
+		{"arredonda em vez de truncar", PtBR.Number(0.999), "0,999"},
+		{"arredonda a segunda casa", PtBR.Number(2.006), "2,01"},
+		{"abaixo de um centavo", PtBR.Number(0.00001234), "0,00001234"},
+		{"zero", PtBR.Number(0), "0,00"},
+		{"negativo", PtBR.Number(-1500), "-1.500,00"},
+		{"sem -0,00", PtBR.Fixed(-0.001, 2), "0,00"},
+		{"NaN", PtBR.Number(math.NaN()), "-"},
+		{"real", PtBR.Money(1234.5, "BRL"), "R$ 1.234,50"},
+		{"dólar pt-BR", PtBR.Money(10, "usd"), "US$ 10,00"},
+		{"dólar en", EnUS.Money(10, "USD"), "$10.00"},
+		{"dólar negativo", PtBR.Money(-5, "USD"), "-US$ 5,00"},
+		{"moeda desconhecida", EnUS.Money(1, "CHF"), "CHF 1.00"},
+		{"percentual", PtBR.Percent(12.345, false), "12,35%"},
+		{"percentual com sinal", EnUS.Percent(3, true), "+3.00%"},
+		{"percentual zero com sinal", EnUS.Percent(0.001, true), "0.00%"},
+		{"compacto tri", PtBR.CompactMoney(1.2e12, "BRL"), "R$ 1,2 tri"},
+		{"compacto B", EnUS.CompactMoney(3.4e9, "USD"), "$3.4B"},
+		{"compacto arredonda para a unidade seguinte", PtBR.CompactNumber(999.96e9), "1 tri"},
+		{"compacto abaixo de mil", PtBR.CompactNumber(999), "999,00"},
+		{"compacto centenas de milhões", EnUS.CompactNumber(123.4e6), "123M"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: %q, quero %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestForLanguage(t *testing.T) {
+	for lang, want := range map[string]Locale{"pt-BR": PtBR, "en": EnUS, "en-US": EnUS, "en_US.UTF-8": EnUS, "": PtBR, "es": PtBR} {
+		if got := ForLanguage(lang); got.Decimal != want.Decimal {
+			t.Errorf("ForLanguage(%q) usa decimal %q, quero %q", lang, got.Decimal, want.Decimal)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Alerta cria, lista e remove alertas de preço da conversa
+// (ex: !alerta btc > 600000 brl, !alerta eth abaixo 2000 usd recorrente, !alerta lista, !alerta remover 3)
+func Alerta(ctx context.Context, conv transport.Conversation, args string) {
+	chat := conv.Chat().String()
+
+	if args == "" {
+		args = "lista"
+	}
+	if _, ok := cutPrefixWord(args, "lista", "listar"); ok {
+		msg, err := services.ListPriceAlerts(ctx, chat)
+		if err != nil {
+			conv.Reply(ctx, err.Error())
+			return
+		}
+		conv.Reply(ctx, msg)
+		return
+	}
+	if rest, ok := cutPrefixWord(args, "remover", "apagar", "excluir"); ok {
+		if err := services.DeletePriceAlert(ctx, chat, rest); err != nil {
+			conv.Reply(ctx, err.Error())
+			return
+		}
+		conv.Reply(ctx, fmt.Sprintf("🗑️ Alerta %s removido.", rest))
+		return
+	}
+
+	msg, err := services.CreatePriceAlert(ctx, chat, conv.Sender().ToNonAD().String(), args)
+	if err != nil {
+		ReplyError(ctx, conv, err)
+		return
+	}
+	conv.Reply(ctx, msg)
+}
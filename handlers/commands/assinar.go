@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Assinar inscreve a conversa nos avisos de um evento (ex: !assinar ath, !assinar ath btc eth)
+func Assinar(ctx context.Context, conv transport.Conversation, args string) {
+	msg, err := services.Subscribe(ctx, conv.Chat().String(), args)
+	if err != nil {
+		ReplyError(ctx, conv, err)
+		return
+	}
+	conv.Reply(ctx, msg)
+}
+
+// Cancelar cancela as assinaturas da conversa (ex: !cancelar ath, !cancelar ath btc)
+func Cancelar(ctx context.Context, conv transport.Conversation, args string) {
+	msg, err := services.Unsubscribe(ctx, conv.Chat().String(), args)
+	if err != nil {
+		ReplyError(ctx, conv, err)
+		return
+	}
+	conv.Reply(ctx, msg)
+}
+
+// Assinaturas lista os avisos assinados pela conversa
+func Assinaturas(ctx context.Context, conv transport.Conversation) {
+	msg, err := services.ListSubscriptions(ctx, conv.Chat().String())
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+	conv.Reply(ctx, msg)
+}
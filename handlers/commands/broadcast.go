@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+const listaUsage = `📋 *Listas de transmissão*
+
+- !lista → Mostra todas as listas
+- !lista criar <nome>
+- !lista apagar <nome>
+- !lista ver <nome>
+- !lista add <nome> <número|grupo@g.us|aqui> ...
+- !lista remover <nome> <número|grupo@g.us|aqui> ...
+
+📣 !broadcast <lista> <texto>
+📊 !relatorio [id]`
+
+// Lista gerencia as listas de transmissão (criar, apagar, ver, adicionar e remover membros)
+func Lista(ctx context.Context, conv transport.Conversation, args string) {
+	if store.DB == nil {
+		conv.Reply(ctx, "⚠️ Listas indisponíveis: banco de dados desconectado.")
+		return
+	}
+
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		lists, err := store.ListBroadcastLists(ctx)
+		if err != nil {
+			conv.Reply(ctx, err.Error())
+			return
+		}
+		if len(lists) == 0 {
+			conv.Reply(ctx, "📋 Nenhuma lista criada.\n\n"+listaUsage)
+			return
+		}
+		var b strings.Builder
+		b.WriteString("📋 *Listas de transmissão*\n\n")
+		for _, l := range lists {
+			b.WriteString(fmt.Sprintf("- *%s* (%d membros)\n", l.Name, l.Members))
+		}
+		conv.Reply(ctx, b.String())
+		return
+	}
+
+	if len(fields) < 2 {
+		conv.Reply(ctx, listaUsage)
+		return
+	}
+
+	action := strings.ToLower(fields[0])
+	name := strings.ToLower(fields[1])
+
+	switch action {
+	case "criar":
+		if err := store.CreateBroadcastList(ctx, name, conv.Sender().User); err != nil {
+			conv.Reply(ctx, err.Error())
+			return
+		}
+		conv.Reply(ctx, fmt.Sprintf("✅ Lista *%s* criada.", name))
+
+	case "apagar":
+		if err := store.DeleteBroadcastList(ctx, name); err != nil {
+			conv.Reply(ctx, err.Error())
+			return
+		}
+		conv.Reply(ctx, fmt.Sprintf("🗑️ Lista *%s* apagada.", name))
+
+	case "ver":
+		members, err := store.BroadcastMembers(ctx, name)
+		if err != nil {
+			conv.Reply(ctx, err.Error())
+			return
+		}
+		if len(members) == 0 {
+			conv.Reply(ctx, fmt.Sprintf("📋 A lista *%s* está vazia.", name))
+			return
+		}
+		conv.Reply(ctx, fmt.Sprintf("📋 *%s* (%d membros)\n\n- %s", name, len(members), strings.Join(members, "\n- ")))
+
+	case "add", "adicionar", "remover", "rm":
+		if len(fields) < 3 {
+			conv.Reply(ctx, listaUsage)
+			return
+		}
+
+		var jids []string
+		for _, raw := range fields[2:] {
+			jid, err := services.ParseRecipient(raw, conv.Chat())
+			if err != nil {
+				conv.Reply(ctx, err.Error())
+				return
+			}
+			jids = append(jids, jid.String())
+		}
+
+		if action == "remover" || action == "rm" {
+			n, err := store.RemoveBroadcastMembers(ctx, name, jids)
+			if err != nil {
+				conv.Reply(ctx, err.Error())
+				return
+			}
+			conv.Reply(ctx, fmt.Sprintf("➖ %d membro(s) removido(s) da lista *%s*.", n, name))
+			return
+		}
+
+		n, err := store.AddBroadcastMembers(ctx, name, jids)
+		if err != nil {
+			conv.Reply(ctx, err.Error())
+			return
+		}
+		conv.Reply(ctx, fmt.Sprintf("➕ %d membro(s) adicionado(s) à lista *%s*.", n, name))
+
+	default:
+		conv.Reply(ctx, listaUsage)
+	}
+}
+
+// Broadcast envia um texto para todos os membros de uma lista (ex: !broadcast equipe Reunião às 15h)
+func Broadcast(ctx context.Context, conv transport.Conversation, args string) {
+	name, content, _ := strings.Cut(strings.TrimSpace(args), " ")
+	content = strings.TrimSpace(content)
+	if name == "" || content == "" {
+		conv.Reply(ctx, "⚠️ Uso: !broadcast <lista> <texto>")
+		return
+	}
+
+	id, total, err := services.Broadcast(ctx, strings.ToLower(name), content, conv.Sender().User)
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+
+	conv.Reply(ctx, fmt.Sprintf(
+		"📣 Broadcast *#%d* enfileirado para %d destinatário(s) da lista *%s*.\n📊 Acompanhe com !relatorio %d",
+		id, total, strings.ToLower(name), id,
+	))
+}
+
+// Relatorio mostra entregas e leituras de um broadcast, ou os últimos envios sem argumento
+func Relatorio(ctx context.Context, conv transport.Conversation, args string) {
+	if store.DB == nil {
+		conv.Reply(ctx, "⚠️ Relatórios indisponíveis: banco de dados desconectado.")
+		return
+	}
+
+	args = strings.TrimPrefix(strings.TrimSpace(args), "#")
+	if args == "" {
+		recent, err := store.RecentBroadcasts(ctx, 10)
+		if err != nil {
+			conv.Reply(ctx, err.Error())
+			return
+		}
+		if len(recent) == 0 {
+			conv.Reply(ctx, "📊 Nenhum broadcast enviado ainda.")
+			return
+		}
+		var b strings.Builder
+		b.WriteString("📊 *Últimos broadcasts*\n\n")
+		for _, r := range recent {
+			b.WriteString(fmt.Sprintf("*#%d* %s → %s\n", r.ID, r.CreatedAt.Local().Format("02/01 15:04"), r.ListName))
+		}
+		b.WriteString("\n💡 Use !relatorio <id> para detalhes.")
+		conv.Reply(ctx, b.String())
+		return
+	}
+
+	id, err := strconv.ParseInt(args, 10, 64)
+	if err != nil {
+		conv.Reply(ctx, "⚠️ Uso: !relatorio <id>")
+		return
+	}
+
+	r, err := store.GetBroadcastReport(ctx, id)
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+
+	conv.Reply(ctx, fmt.Sprintf(
+		"📊 *Broadcast #%d* → %s\n🕒 %s por %s\n\n"+
+			"👥 Destinatários: %d\n⏳ Pendentes: %d\n📤 Enviados: %d\n📬 Entregues: %d\n👀 Lidos: %d\n❌ Falhas: %d",
+		r.ID, r.ListName, r.CreatedAt.Local().Format("02/01/2006 15:04"), r.CreatedBy,
+		r.Total, r.Pending, r.Sent, r.Delivered, r.Read, r.Failed,
+	))
+}
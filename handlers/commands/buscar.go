@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+const choiceTTL = 5 * time.Minute
+
+// pendingChoice é um comando com moeda ambígua aguardando o usuário responder com o número do candidato
+type pendingChoice struct {
+	text       string
+	alias      string
+	candidates []string // IDs na ordem exibida
+	expires    time.Time
+}
+
+var (
+	pendingMu      sync.Mutex
+	pendingChoices = map[string]pendingChoice{} // chat|remetente → escolha pendente
+)
+
+// Buscar lista as moedas que correspondem ao termo (ex: !buscar pepe)
+func Buscar(ctx context.Context, conv transport.Conversation, args string) {
+	msg, err := services.SearchCoins(args)
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+	conv.Reply(ctx, msg)
+}
+
+// ReplyError responde com o erro; se a moeda for ambígua, guarda o comando e pede que o usuário escolha
+func ReplyError(ctx context.Context, conv transport.Conversation, err error) {
+	var ambiguous *services.AmbiguousCoinError
+	if !errors.As(err, &ambiguous) {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+
+	ids := make([]string, len(ambiguous.Candidates))
+	for i, c := range ambiguous.Candidates {
+		ids[i] = c.ID
+	}
+
+	pendingMu.Lock()
+	pendingChoices[choiceKey(conv)] = pendingChoice{
+		text:       conv.Text(),
+		alias:      ambiguous.Alias,
+		candidates: ids,
+		expires:    time.Now().Add(choiceTTL),
+	}
+	pendingMu.Unlock()
+
+	conv.Reply(ctx, ambiguous.Error())
+}
+
+// ResolveChoice verifica se a mensagem responde a uma escolha pendente (ex: "2") e devolve
+// o comando original com o alias trocado pelo ID da moeda escolhida
+func ResolveChoice(conv transport.Conversation) (string, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(conv.Text()))
+	if err != nil {
+		return "", false
+	}
+
+	key := choiceKey(conv)
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+
+	choice, ok := pendingChoices[key]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(choice.expires) {
+		delete(pendingChoices, key)
+		return "", false
+	}
+	if n < 1 || n > len(choice.candidates) {
+		return "", false
+	}
+	delete(pendingChoices, key)
+
+	fields := strings.Fields(choice.text)
+	for i, f := range fields {
+		switch {
+		case strings.EqualFold(f, choice.alias):
+			fields[i] = choice.candidates[n-1]
+		case strings.EqualFold(f, "!"+choice.alias):
+			fields[i] = "!" + choice.candidates[n-1]
+		default:
+			continue
+		}
+		break
+	}
+	return strings.Join(fields, " "), true
+}
+
+func choiceKey(conv transport.Conversation) string {
+	return conv.Chat().String() + "|" + conv.Sender().ToNonAD().String()
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Carteira registra e mostra a carteira pessoal de quem envia o comando
+// (ex: !carteira, !carteira add btc 0.15 @ 320000, !carteira vender eth 1, !carteira historico, !carteira remover 3).
+// A carteira é privada: pedidos feitos em grupo são respondidos no privado.
+func Carteira(ctx context.Context, conv transport.Conversation, args string) {
+	owner := conv.Sender().ToNonAD().String()
+
+	var msg string
+	var err error
+	if rest, ok := cutPrefixWord(args, "add", "adicionar", "comprar", "compra"); ok {
+		msg, err = services.AddPortfolioTransaction(ctx, owner, rest, false)
+	} else if rest, ok := cutPrefixWord(args, "vender", "venda", "sell"); ok {
+		msg, err = services.AddPortfolioTransaction(ctx, owner, rest, true)
+	} else if _, ok := cutPrefixWord(args, "historico", "histórico", "transacoes", "transações"); ok {
+		msg, err = services.PortfolioTransactions(ctx, owner)
+	} else if rest, ok := cutPrefixWord(args, "remover", "apagar", "excluir"); ok {
+		if err = services.DeletePortfolioTransaction(ctx, owner, rest); err == nil {
+			msg = "🗑️ Transação " + rest + " removida."
+		}
+	} else if args == "" {
+		msg, err = services.Portfolio(ctx, owner)
+	} else {
+		replyPrivately(ctx, conv, "⚠️ Uso: !carteira [add <moeda> <quantidade> [@ <preço>] | vender <moeda> <quantidade> [@ <preço>] | historico | remover <id>]")
+		return
+	}
+
+	if err != nil {
+		// A escolha entre moedas homônimas precisa ser respondida na própria conversa
+		var ambiguous *services.AmbiguousCoinError
+		if errors.As(err, &ambiguous) {
+			ReplyError(ctx, conv, err)
+			return
+		}
+		msg = err.Error()
+	}
+	replyPrivately(ctx, conv, msg)
+}
+
+// replyPrivately responde no privado de quem enviou o comando; em grupo, só avisa que a resposta foi enviada
+func replyPrivately(ctx context.Context, conv transport.Conversation, msg string) {
+	if !conv.IsGroup() {
+		conv.Reply(ctx, msg)
+		return
+	}
+	services.SendReply(ctx, conv.Messenger(), conv.Sender().ToNonAD(), msg)
+	conv.Reply(ctx, "📩 Respondi no seu privado.")
+}
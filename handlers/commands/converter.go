@@ -0,0 +1,18 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Converter converte valores entre cripto e moedas fiduciárias (ex: !converter 0,05 btc brl)
+func Converter(ctx context.Context, conv transport.Conversation, args string) {
+	msg, err := services.Convert(args)
+	if err != nil {
+		ReplyError(ctx, conv, err)
+		return
+	}
+	conv.Reply(ctx, msg)
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Cotacao mostra uma tabela compacta com várias moedas (ex: !cotacao btc eth sol por variacao).
+// Sem moedas, usa a lista padrão da conversa, definida com !cotacao padrao <moedas>.
+func Cotacao(ctx context.Context, conv transport.Conversation, args string) {
+	chat := conv.Chat().String()
+
+	if rest, ok := cutPrefixWord(args, "padrao", "padrão"); ok {
+		coins := strings.Fields(strings.ToLower(rest))
+		if len(coins) == 0 {
+			conv.Reply(ctx, fmt.Sprintf("📋 Lista padrão desta conversa: %s\n\n💡 Altere com !cotacao padrao btc eth sol",
+				strings.ToUpper(strings.Join(services.ChatDefaultCoins(ctx, chat), " "))))
+			return
+		}
+		if err := services.SetChatDefaultCoins(ctx, chat, coins); err != nil {
+			ReplyError(ctx, conv, err)
+			return
+		}
+		conv.Reply(ctx, fmt.Sprintf("✅ Lista padrão atualizada: %s", strings.ToUpper(strings.Join(coins, " "))))
+		return
+	}
+
+	coins, sortBy, err := services.ParseTableArgs(args)
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+	if len(coins) == 0 {
+		coins = services.ChatDefaultCoins(ctx, chat)
+	}
+
+	table, err := services.RenderQuoteTable(coins, sortBy)
+	if err != nil {
+		ReplyError(ctx, conv, err)
+		return
+	}
+	conv.Reply(ctx, table)
+}
+
+// cutPrefixWord verifica se args começa com uma das palavras (sem diferenciar caixa) e devolve o restante
+func cutPrefixWord(args string, words ...string) (string, bool) {
+	first, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	for _, w := range words {
+		if strings.EqualFold(first, w) {
+			return strings.TrimSpace(rest), true
+		}
+	}
+	return "", false
+}
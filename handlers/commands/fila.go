@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+var outboxStatusLabels = map[string]string{
+	store.OutboxPending: "⏳ Pendente",
+	store.OutboxSent:    "✅ Enviada",
+	store.OutboxFailed:  "❌ Falhou",
+}
+
+// Fila mostra o resumo da fila de saída ou o status de uma mensagem específica (ex: !fila 42)
+func Fila(ctx context.Context, conv transport.Conversation, args string) {
+	if store.DB == nil {
+		conv.Reply(ctx, "⚠️ Fila de saída indisponível.")
+		return
+	}
+
+	if args == "" {
+		stats, err := store.OutboxStats(ctx)
+		if err != nil {
+			conv.Reply(ctx, fmt.Sprintf("⚠️ %v", err))
+			return
+		}
+		conv.Reply(ctx, fmt.Sprintf(
+			"📮 *Fila de saída*\n\n%s: %d\n%s: %d\n%s: %d\n\n💡 Use !fila <id> para ver uma mensagem.",
+			outboxStatusLabels[store.OutboxPending], stats[store.OutboxPending],
+			outboxStatusLabels[store.OutboxSent], stats[store.OutboxSent],
+			outboxStatusLabels[store.OutboxFailed], stats[store.OutboxFailed],
+		))
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(args, "#"), 10, 64)
+	if err != nil {
+		conv.Reply(ctx, "⚠️ Uso: !fila <id>")
+		return
+	}
+
+	m, err := services.OutboxStatus(ctx, id)
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📨 *Mensagem #%d*\n\n", m.ID))
+	b.WriteString(fmt.Sprintf("👤 Destino: %s\n", m.Recipient))
+	b.WriteString(fmt.Sprintf("📌 Status: %s\n", outboxStatusLabels[m.Status]))
+	b.WriteString(fmt.Sprintf("🔁 Tentativas: %d\n", m.Attempts))
+	b.WriteString(fmt.Sprintf("🕒 Criada em: %s\n", m.CreatedAt.Local().Format("02/01/2006 15:04:05")))
+	if m.SentAt != nil {
+		b.WriteString(fmt.Sprintf("📤 Enviada em: %s\n", m.SentAt.Local().Format("02/01/2006 15:04:05")))
+	} else if m.Status == store.OutboxPending {
+		b.WriteString(fmt.Sprintf("⏭️ Próxima tentativa: %s\n", m.NextAttemptAt.Local().Format("02/01/2006 15:04:05")))
+	}
+	if m.LastError != "" {
+		b.WriteString(fmt.Sprintf("⚠️ Último erro: %s\n", m.LastError))
+	}
+	b.WriteString(fmt.Sprintf("\n💬 %s", m.Preview))
+
+	conv.Reply(ctx, b.String())
+}
@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Grafico envia o gráfico de preço da moeda como imagem (ex: !grafico btc 7d, !grafico eth 30d velas usd)
+func Grafico(ctx context.Context, conv transport.Conversation, args string) {
+	png, caption, err := services.GetPriceChart(args)
+	if err != nil {
+		ReplyError(ctx, conv, err)
+		return
+	}
+	if err := services.Send(ctx, conv.Messenger(), conv.Chat(), services.Image{Data: png, Caption: caption}); err != nil {
+		conv.Reply(ctx, "❌ Erro ao enviar o gráfico: "+err.Error())
+	}
+}
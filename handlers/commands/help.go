@@ -2,30 +2,20 @@ package commands
 
 import (
 	"context"
+	"log"
+
+	"github.com/faysk/whatsapp-bot/config"
 	"github.com/faysk/whatsapp-bot/services"
-	"go.mau.fi/whatsmeow"
-	waTypes "go.mau.fi/whatsmeow/types"
+	"github.com/faysk/whatsapp-bot/transport"
 )
 
 // Help mostra os comandos e interações disponíveis com o bot
-func Help(ctx context.Context, client *whatsmeow.Client, chat waTypes.JID) {
-	msg := `📖 *Comandos e interações disponíveis*:
-
-🧪 *Comandos tradicionais*:
-- !ping → Testa se o bot está online
-- !help → Exibe esta mensagem de ajuda
-
-🤖 *Interações naturais com o bot*:
-- Diga: "ping", "teste", "tá aí", "responde", etc.
-- O bot vai responder com frases aleatórias
-
-🌞 *Saudações automáticas*:
-- "bom dia", "boa tarde", "boa noite"
-- "oi", "olá", "salve", "opa"
-
-ℹ️ *Mais funções em breve...*
-
-💡 Dica: use linguagem natural! O bot entende mais do que apenas comandos. 😉`
-
-	services.SendReply(ctx, client, chat, msg)
+func Help(ctx context.Context, conv transport.Conversation) {
+	msg, err := services.RenderTemplate("help", struct{ BotName string }{config.AppConfig.BotName})
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		msg = "📖 Ajuda indisponível no momento."
+	}
+
+	conv.Reply(ctx, msg)
 }
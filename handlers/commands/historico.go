@@ -0,0 +1,18 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Historico compara o preço de uma data passada com o atual (ex: !historico btc 15/01/2024, !btc em ontem)
+func Historico(ctx context.Context, conv transport.Conversation, args string) {
+	msg, err := services.GetPriceHistory(args)
+	if err != nil {
+		ReplyError(ctx, conv, err)
+		return
+	}
+	conv.Reply(ctx, msg)
+}
@@ -6,9 +6,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/faysk/whatsapp-bot/services"
-	"go.mau.fi/whatsmeow"
-	waTypes "go.mau.fi/whatsmeow/types"
+	"github.com/faysk/whatsapp-bot/transport"
 )
 
 var interacoes = map[string][]string{
@@ -35,14 +33,14 @@ var interacoes = map[string][]string{
 }
 
 // DetectInteracao verifica se a mensagem é uma interação comum com o bot
-func DetectInteracao(ctx context.Context, client *whatsmeow.Client, chat waTypes.JID, text string) bool {
-	text = normalize(text)
+func DetectInteracao(ctx context.Context, conv transport.Conversation) bool {
+	text := normalize(conv.Text())
 	rand.Seed(time.Now().UnixNano())
 
 	for chave, respostas := range interacoes {
 		if strings.Contains(text, chave) {
 			resposta := respostas[rand.Intn(len(respostas))]
-			services.SendReply(ctx, client, chat, resposta)
+			conv.Reply(ctx, resposta)
 			return true
 		}
 	}
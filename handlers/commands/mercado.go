@@ -0,0 +1,18 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Mercado mostra o panorama do mercado cripto: market cap, dominância, top moedas e sentimento (ex: !mercado 20)
+func Mercado(ctx context.Context, conv transport.Conversation, args string) {
+	msg, err := services.GetMarketOverview(args)
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+	conv.Reply(ctx, msg)
+}
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Monitor mostra e altera as moedas e o intervalo do monitor de ATH
+// (ex: !monitor, !monitor moedas btc eth sol, !monitor adicionar ada, !monitor remover usdt, !monitor intervalo 10m)
+func Monitor(ctx context.Context, conv transport.Conversation, args string) {
+	var err error
+	if rest, ok := cutPrefixWord(args, "moedas", "lista"); ok {
+		err = services.SetMonitoredCoins(ctx, strings.Fields(rest))
+	} else if rest, ok := cutPrefixWord(args, "adicionar", "add"); ok {
+		err = services.AddMonitoredCoins(ctx, strings.Fields(rest))
+	} else if rest, ok := cutPrefixWord(args, "remover", "rm"); ok {
+		err = services.RemoveMonitoredCoins(ctx, strings.Fields(rest))
+	} else if rest, ok := cutPrefixWord(args, "intervalo"); ok {
+		err = services.SetMonitorInterval(ctx, rest)
+	} else if args != "" {
+		conv.Reply(ctx, "⚠️ Uso: !monitor [moedas <lista> | adicionar <moedas> | remover <moedas> | intervalo <tempo>]")
+		return
+	}
+	if err != nil {
+		ReplyError(ctx, conv, err)
+		return
+	}
+
+	msg, err := services.MonitorStatus(ctx)
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+	conv.Reply(ctx, msg)
+}
@@ -6,9 +6,7 @@ import (
 	"regexp"
 	"time"
 
-	"github.com/faysk/whatsapp-bot/services"
-	"go.mau.fi/whatsmeow"
-	waTypes "go.mau.fi/whatsmeow/types"
+	"github.com/faysk/whatsapp-bot/transport"
 )
 
 var saudacoes = map[string][]string{
@@ -62,8 +60,8 @@ var aliases = map[string][]string{
 }
 
 // DetectSaudacao tenta identificar e responder a uma saudação
-func DetectSaudacao(ctx context.Context, client *whatsmeow.Client, chat waTypes.JID, text string) bool {
-	text = normalize(text)
+func DetectSaudacao(ctx context.Context, conv transport.Conversation) bool {
+	text := normalize(conv.Text())
 	words := tokenize(text)
 	rand.Seed(time.Now().UnixNano())
 
@@ -74,7 +72,7 @@ func DetectSaudacao(ctx context.Context, client *whatsmeow.Client, chat waTypes.
 					respostas := saudacoes[categoria]
 					if len(respostas) > 0 {
 						resposta := respostas[rand.Intn(len(respostas))]
-						services.SendReply(ctx, client, chat, resposta)
+						conv.Reply(ctx, resposta)
 						return true
 					}
 				}
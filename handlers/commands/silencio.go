@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Silencio mostra e altera o horário de silêncio e o fuso da conversa
+// (ex: !silencio, !silencio 22:00-07:00, !silencio fuso America/Sao_Paulo, !silencio off)
+func Silencio(ctx context.Context, conv transport.Conversation, args string) {
+	chat := conv.Chat().String()
+
+	var err error
+	if rest, ok := cutPrefixWord(args, "fuso", "timezone", "tz"); ok {
+		err = services.SetChatTimezone(ctx, chat, rest)
+	} else if _, ok := cutPrefixWord(args, "off", "desligar", "desativar", "nenhum"); ok {
+		err = services.DisableQuietHours(ctx, chat)
+	} else if args != "" {
+		err = services.SetQuietHours(ctx, chat, args)
+	}
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+
+	msg, err := services.QuietStatus(ctx, chat)
+	if err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+	conv.Reply(ctx, msg)
+}
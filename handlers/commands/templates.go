@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
+)
+
+// Templates recarrega os templates de mensagens (embutidos + TEMPLATES_DIR) sem reiniciar o bot
+func Templates(ctx context.Context, conv transport.Conversation) {
+	if err := services.LoadTemplates(); err != nil {
+		conv.Reply(ctx, err.Error())
+		return
+	}
+	conv.Reply(ctx, "📝 Templates recarregados com sucesso.")
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/transport"
+	"go.mau.fi/whatsmeow/types"
+)
+
+var (
+	todosMu   sync.Mutex
+	todosLast = map[string]time.Time{} // grupo → último !todos
+)
+
+// Todos menciona todos os participantes do grupo (ex: !todos reunião em 5 minutos)
+func Todos(ctx context.Context, conv transport.Conversation, args string) {
+	if !conv.IsGroup() {
+		conv.Reply(ctx, "⚠️ O !todos só funciona em grupos.")
+		return
+	}
+
+	// Limita a frequência por grupo para evitar spam de notificações
+	chat := conv.Chat().String()
+
+	todosMu.Lock()
+	last := todosLast[chat]
+	wait := config.AppConfig.TodosCooldown - time.Since(last)
+	if wait > 0 {
+		todosMu.Unlock()
+		conv.Reply(ctx, fmt.Sprintf("⏳ Aguarde %s para usar o !todos novamente.", wait.Round(time.Second)))
+		return
+	}
+	// Reserva a vez do grupo já aqui (dois !todos simultâneos não passam juntos) e a devolve se nada for enviado
+	todosLast[chat] = time.Now()
+	todosMu.Unlock()
+
+	if err := sendTodos(ctx, conv, args); err != nil {
+		todosMu.Lock()
+		todosLast[chat] = last
+		todosMu.Unlock()
+		conv.Reply(ctx, err.Error())
+	}
+}
+
+// sendTodos envia a mensagem marcando cada participante (e quem chamou) uma única vez
+func sendTodos(ctx context.Context, conv transport.Conversation, args string) error {
+	participants, err := conv.Messenger().GroupParticipants(ctx, conv.Chat())
+	if err != nil {
+		return err
+	}
+	if len(participants) == 0 {
+		return fmt.Errorf("⚠️ Nenhum participante para mencionar.")
+	}
+
+	seen := map[types.JID]bool{}
+	mentions := make([]types.JID, 0, len(participants)+1)
+	for _, jid := range append(participants, conv.Sender()) {
+		jid = jid.ToNonAD()
+		if !seen[jid] {
+			seen[jid] = true
+			mentions = append(mentions, jid)
+		}
+	}
+
+	text := strings.TrimSpace(args)
+	if text == "" {
+		text = "📣 Atenção, pessoal!"
+	}
+	text = fmt.Sprintf("%s\n\n— chamado por @%s", text, conv.Sender().User)
+
+	msg, preview := transport.TextMessage(text, mentions)
+	if err := conv.Messenger().Send(ctx, conv.Chat(), msg, preview); err != nil {
+		return fmt.Errorf("❌ Não foi possível enviar o !todos: %w", err)
+	}
+	return nil
+}
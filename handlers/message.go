@@ -11,19 +11,14 @@ import (
 	"github.com/faysk/whatsapp-bot/openai"
 	"github.com/faysk/whatsapp-bot/services"
 	"github.com/faysk/whatsapp-bot/store"
-	"go.mau.fi/whatsmeow"
-	waTypes "go.mau.fi/whatsmeow/types"
-	"go.mau.fi/whatsmeow/types/events"
+	"github.com/faysk/whatsapp-bot/transport"
 )
 
-func HandleCommand(ctx context.Context, client *whatsmeow.Client, _ waTypes.JID, text string, msg *events.Message) {
-	text = strings.TrimSpace(text)
-	lower := strings.ToLower(text)
-
+// HandleCommand interpreta a mensagem recebida e executa o comando correspondente
+func HandleCommand(ctx context.Context, conv transport.Conversation) {
 	logPrefix := fmt.Sprintf("[%s]", config.AppConfig.BotName)
-	sender := msg.Info.Sender.User
-	isGroup := msg.Info.IsGroup
-	chat := msg.Info.Chat
+	sender := conv.Sender().User
+	isGroup := conv.IsGroup()
 
 	if config.AppConfig.RestrictToGroup && !isGroup {
 		log.Printf("%s 🚫 Ignorando mensagem privada (RESTRICT_TO_GROUP=true)", logPrefix)
@@ -35,15 +30,24 @@ func HandleCommand(ctx context.Context, client *whatsmeow.Client, _ waTypes.JID,
 		return
 	}
 
+	// 🔢 Resposta a uma escolha de moeda ambígua (ex: "2" depois de !pepe): refaz o comando original
+	if rewritten, ok := commands.ResolveChoice(conv); ok {
+		log.Printf("%s 🔢 Escolha de moeda de %s: %s", logPrefix, sender, rewritten)
+		conv = transport.NewConversation(conv.Messenger(), conv.Chat(), conv.Sender(), rewritten, conv.Event())
+	}
+
+	text := strings.TrimSpace(conv.Text())
+	lower := strings.ToLower(text)
+
 	// 🎯 Comandos com prefixo "!"
 	switch lower {
 	case "!ping":
 		log.Printf("%s 🟢 Comando !ping de %s", logPrefix, sender)
-		commands.Ping(ctx, client, chat)
+		commands.Ping(ctx, conv)
 		return
 	case "!help":
 		log.Printf("%s 📘 Comando !help de %s", logPrefix, sender)
-		commands.Help(ctx, client, chat)
+		commands.Help(ctx, conv)
 		return
 	case "!cryptonews":
 		log.Printf("%s 📰 Comando !cryptonews de %s", logPrefix, sender)
@@ -53,33 +57,165 @@ func HandleCommand(ctx context.Context, client *whatsmeow.Client, _ waTypes.JID,
 			if err != nil {
 				msg += "\nDetalhes: " + err.Error()
 			}
-			services.SendReply(ctx, client, chat, msg)
+			conv.Reply(ctx, msg)
+			return
+		}
+		conv.Reply(ctx, news)
+		return
+	}
+
+	// 💱 Conversão entre cripto e moedas (ex: !converter 0,05 btc brl)
+	if args, ok := matchCommand(text, "!converter"); ok {
+		log.Printf("%s 💱 Comando !converter de %s", logPrefix, sender)
+		commands.Converter(ctx, conv, args)
+		return
+	}
+
+	// 🌎 Panorama do mercado (ex: !mercado ou !mercado 20)
+	if args, ok := matchCommand(text, "!mercado"); ok {
+		log.Printf("%s 🌎 Comando !mercado de %s", logPrefix, sender)
+		commands.Mercado(ctx, conv, args)
+		return
+	}
+
+	// 🔍 Busca no diretório de moedas (ex: !buscar pepe)
+	if args, ok := matchCommand(text, "!buscar"); ok {
+		log.Printf("%s 🔍 Comando !buscar de %s", logPrefix, sender)
+		commands.Buscar(ctx, conv, args)
+		return
+	}
+
+	// 📊 Tabela de cotações (ex: !cotacao btc eth sol por variacao)
+	for _, name := range []string{"!cotacao", "!cotação"} {
+		if args, ok := matchCommand(text, name); ok {
+			log.Printf("%s 📊 Comando !cotacao de %s", logPrefix, sender)
+			commands.Cotacao(ctx, conv, args)
+			return
+		}
+	}
+
+	// 📈 Gráfico de preço (ex: !grafico btc 7d velas)
+	for _, name := range []string{"!grafico", "!gráfico"} {
+		if args, ok := matchCommand(text, name); ok {
+			log.Printf("%s 📈 Comando !grafico de %s", logPrefix, sender)
+			commands.Grafico(ctx, conv, args)
+			return
+		}
+	}
+
+	// 📅 Preço em uma data passada (ex: !historico btc 15/01/2024)
+	for _, name := range []string{"!historico", "!histórico"} {
+		if args, ok := matchCommand(text, name); ok {
+			log.Printf("%s 📅 Comando !historico de %s", logPrefix, sender)
+			commands.Historico(ctx, conv, args)
+			return
+		}
+	}
+
+	// 🔔 Alertas de preço (ex: !alerta btc > 600000 brl, !alertas)
+	for _, name := range []string{"!alerta", "!alertas"} {
+		if args, ok := matchCommand(text, name); ok {
+			log.Printf("%s 🔔 Comando !alerta de %s", logPrefix, sender)
+			commands.Alerta(ctx, conv, args)
 			return
 		}
-		services.SendReply(ctx, client, chat, news)
+	}
+
+	// 💼 Carteira pessoal (ex: !carteira, !carteira add btc 0.15 @ 320000); em grupo, responde no privado
+	if args, ok := matchCommand(text, "!carteira"); ok {
+		log.Printf("%s 💼 Comando !carteira de %s", logPrefix, sender)
+		commands.Carteira(ctx, conv, args)
+		return
+	}
+
+	// 📬 Assinaturas de avisos por conversa (ex: !assinar ath btc eth, !cancelar ath, !assinaturas)
+	if args, ok := matchCommand(text, "!assinar"); ok {
+		log.Printf("%s 📬 Comando !assinar de %s", logPrefix, sender)
+		commands.Assinar(ctx, conv, args)
+		return
+	}
+	if args, ok := matchCommand(text, "!cancelar"); ok {
+		log.Printf("%s 📬 Comando !cancelar de %s", logPrefix, sender)
+		commands.Cancelar(ctx, conv, args)
 		return
 	}
+	if _, ok := matchCommand(text, "!assinaturas"); ok {
+		log.Printf("%s 📬 Comando !assinaturas de %s", logPrefix, sender)
+		commands.Assinaturas(ctx, conv)
+		return
+	}
+
+	// 🌙 Horário de silêncio da conversa (ex: !silencio 22:00-07:00, !silencio fuso America/Sao_Paulo)
+	for _, name := range []string{"!silencio", "!silêncio"} {
+		if args, ok := matchCommand(text, name); ok {
+			log.Printf("%s 🌙 Comando !silencio de %s", logPrefix, sender)
+			commands.Silencio(ctx, conv, args)
+			return
+		}
+	}
 
-	// 💰 Comando cripto por moeda (ex: !btc)
+	// 📮 Status da fila de saída (ex: !fila ou !fila 42)
+	if args, ok := matchCommand(text, "!fila"); ok {
+		log.Printf("%s 📮 Comando !fila de %s", logPrefix, sender)
+		commands.Fila(ctx, conv, args)
+		return
+	}
+
+	// 🛡️ Comandos administrativos: listas, broadcast, templates, menções e monitor
+	for _, name := range []string{"!lista", "!broadcast", "!relatorio", "!templates", "!todos", "!monitor"} {
+		args, ok := matchCommand(text, name)
+		if !ok {
+			continue
+		}
+		if !config.IsAdmin(sender) {
+			log.Printf("%s 🚫 %s negado para %s (não é admin)", logPrefix, name, sender)
+			conv.Reply(ctx, "🚫 Comando restrito a administradores.")
+			return
+		}
+		log.Printf("%s 📣 Comando %s de %s", logPrefix, name, sender)
+		switch name {
+		case "!lista":
+			commands.Lista(ctx, conv, args)
+		case "!broadcast":
+			commands.Broadcast(ctx, conv, args)
+		case "!relatorio":
+			commands.Relatorio(ctx, conv, args)
+		case "!templates":
+			commands.Templates(ctx, conv)
+		case "!todos":
+			commands.Todos(ctx, conv, args)
+		case "!monitor":
+			commands.Monitor(ctx, conv, args)
+		}
+		return
+	}
+
+	// 💰 Cotação por ticker (ex: !btc, !petr4, !dolar) ou cripto em uma data (ex: !btc em ontem)
 	if strings.HasPrefix(lower, "!") {
 		moeda := strings.TrimPrefix(lower, "!")
-		log.Printf("%s 💰 Consulta cripto '%s' de %s", logPrefix, moeda, sender)
-		price, err := services.GetCryptoPrice(moeda)
+		if coin, when, ok := strings.Cut(moeda, " em "); ok {
+			log.Printf("%s 📅 Histórico cripto '%s' em '%s' de %s", logPrefix, coin, when, sender)
+			commands.Historico(ctx, conv, coin+" "+when)
+			return
+		}
+		log.Printf("%s 💰 Consulta de cotação '%s' de %s", logPrefix, moeda, sender)
+		price, err := services.GetPrice(moeda)
 		if err != nil {
-			price = "❌ Erro ao consultar moeda: " + err.Error()
+			commands.ReplyError(ctx, conv, fmt.Errorf("❌ Erro ao consultar cotação: %w", err))
+			return
 		}
-		services.SendReply(ctx, client, chat, price)
+		conv.Reply(ctx, price)
 		return
 	}
 
 	// 🌞 Saudações naturais
-	if commands.DetectSaudacao(ctx, client, chat, text) {
+	if commands.DetectSaudacao(ctx, conv) {
 		log.Printf("%s 🤝 Saudação detectada de %s", logPrefix, sender)
 		return
 	}
 
 	// 🧪 Interações simples tipo "ping", "teste"
-	if commands.DetectInteracao(ctx, client, chat, text) {
+	if commands.DetectInteracao(ctx, conv) {
 		log.Printf("%s 🔄 Interação detectada de %s", logPrefix, sender)
 		return
 	}
@@ -91,16 +227,16 @@ func HandleCommand(ctx context.Context, client *whatsmeow.Client, _ waTypes.JID,
 		if containsAny(lower, []string{"adicione o numero", "adicionar o numero", "adiciona o numero", "adicione o número", "adicionar o número", "adiciona o número"}) {
 			num := extractPhoneNumber(text)
 			if num == "" {
-				services.SendReply(ctx, client, chat, "⚠️ Nenhum número válido encontrado.")
+				conv.Reply(ctx, "⚠️ Nenhum número válido encontrado.")
 				return
 			}
 			if err := store.AddAuthorized(num); err != nil {
-				services.SendReply(ctx, client, chat, "⚠️ Não foi possível adicionar o número.")
+				conv.Reply(ctx, "⚠️ Não foi possível adicionar o número.")
 				return
 			}
 			config.AddDynamicAuthorizedNumbers([]string{num})
 			log.Printf("%s ➕ Número %s adicionado por %s", logPrefix, num, sender)
-			services.SendReply(ctx, client, chat, fmt.Sprintf("✅ Número %s adicionado à lista de autorizados.", num))
+			conv.Reply(ctx, fmt.Sprintf("✅ Número %s adicionado à lista de autorizados.", num))
 			return
 		}
 
@@ -108,16 +244,16 @@ func HandleCommand(ctx context.Context, client *whatsmeow.Client, _ waTypes.JID,
 		if containsAny(lower, []string{"remova o numero", "remover o numero", "remove o numero", "remova o número", "remover o número", "remove o número"}) {
 			num := extractPhoneNumber(text)
 			if num == "" {
-				services.SendReply(ctx, client, chat, "⚠️ Nenhum número válido encontrado.")
+				conv.Reply(ctx, "⚠️ Nenhum número válido encontrado.")
 				return
 			}
 			if err := store.RemoveAuthorized(sender, num); err != nil {
-				services.SendReply(ctx, client, chat, fmt.Sprintf("⚠️ %v", err))
+				conv.Reply(ctx, fmt.Sprintf("⚠️ %v", err))
 				return
 			}
 			config.AppConfig.AuthorizedNumbers = store.LoadAuthorizedNumbers()
 			log.Printf("%s ➖ Número %s removido por %s", logPrefix, num, sender)
-			services.SendReply(ctx, client, chat, fmt.Sprintf("🗑️ Número %s removido da lista de autorizados.", num))
+			conv.Reply(ctx, fmt.Sprintf("🗑️ Número %s removido da lista de autorizados.", num))
 			return
 		}
 
@@ -128,13 +264,25 @@ func HandleCommand(ctx context.Context, client *whatsmeow.Client, _ waTypes.JID,
 			log.Printf("%s ⚠️ Erro na IA: %v", logPrefix, err)
 			reply = "❌ Erro ao consultar a IA: " + err.Error()
 		}
-		services.SendReply(ctx, client, chat, reply)
+		conv.Reply(ctx, reply)
 		return
 	}
 
 	log.Printf("%s ❌ Ignorado: \"%s\" de %s (sem comando nem gatilho)", logPrefix, text, sender)
 }
 
+// matchCommand verifica se o texto é o comando informado e devolve os argumentos com a caixa original
+func matchCommand(text, name string) (string, bool) {
+	if len(text) < len(name) || !strings.EqualFold(text[:len(name)], name) {
+		return "", false
+	}
+	rest := text[len(name):]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\n' {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
 func isAuthorized(sender string) bool {
 	for _, num := range config.AppConfig.AuthorizedNumbers {
 		if sender == num {
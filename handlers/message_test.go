@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/transport"
+	"go.mau.fi/whatsmeow/types"
+)
+
+var (
+	testUser  = types.NewJID("5511999999999", types.DefaultUserServer)
+	testAdmin = types.NewJID("5511888888888", types.DefaultUserServer)
+	testGroup = types.NewJID("120363000000000000", types.GroupServer)
+)
+
+// withConfig troca a configuração global durante o teste
+func withConfig(t *testing.T, cfg config.Config) {
+	t.Helper()
+	saved := config.AppConfig
+	config.AppConfig = cfg
+	t.Cleanup(func() { config.AppConfig = saved })
+}
+
+func testConfig() config.Config {
+	return config.Config{
+		BotName:           "TestBot",
+		AuthorizedNumbers: []string{testUser.User, testAdmin.User},
+		AdminNumbers:      []string{testAdmin.User},
+	}
+}
+
+func TestHandleCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		restrict bool
+		chat     types.JID
+		sender   types.JID
+		text     string
+		want     []string
+	}{
+		{"ping", false, testUser, testUser, "!ping", []string{"🏓 Pong!"}},
+		{"ping com espaços e maiúsculas", false, testUser, testUser, "  !PING ", []string{"🏓 Pong!"}},
+		{"ping em grupo", false, testGroup, testUser, "!ping", []string{"🏓 Pong!"}},
+		{"número não autorizado", false, testUser, types.NewJID("5511000000000", types.DefaultUserServer), "!ping", nil},
+		{"privado com RESTRICT_TO_GROUP", true, testUser, testUser, "!ping", nil},
+		{"grupo com RESTRICT_TO_GROUP", true, testGroup, testUser, "!ping", []string{"🏓 Pong!"}},
+		{"admin negado", false, testUser, testUser, "!monitor intervalo 10m", []string{"🚫 Comando restrito a administradores."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.RestrictToGroup = tt.restrict
+			withConfig(t, cfg)
+
+			mem := transport.NewMemory()
+			HandleCommand(context.Background(), transport.NewMemoryConversation(mem, tt.chat, tt.sender, tt.text))
+
+			got := mem.Texts()
+			if len(got) != len(tt.want) {
+				t.Fatalf("respostas = %q, quero %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("resposta %d = %q, quero %q", i, got[i], tt.want[i])
+				}
+			}
+			for _, s := range mem.Sent() {
+				if s.To != tt.chat {
+					t.Errorf("resposta enviada para %s, quero %s", s.To, tt.chat)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchCommand(t *testing.T) {
+	tests := []struct {
+		text, name string
+		args       string
+		ok         bool
+	}{
+		{"!alerta btc > 600000", "!alerta", "btc > 600000", true},
+		{"!ALERTA lista", "!alerta", "lista", true},
+		{"!alerta", "!alerta", "", true},
+		{"!alerta\nbtc", "!alerta", "btc", true},
+		{"!alertas", "!alerta", "", false},
+		{"!alertas", "!alertas", "", true},
+		{"!assinaturas", "!assinar", "", false},
+		{"!al", "!alerta", "", false},
+	}
+
+	for _, tt := range tests {
+		args, ok := matchCommand(tt.text, tt.name)
+		if ok != tt.ok || args != tt.args {
+			t.Errorf("matchCommand(%q, %q) = (%q, %v), quero (%q, %v)", tt.text, tt.name, args, ok, tt.args, tt.ok)
+		}
+	}
+}
+
+func TestTodos(t *testing.T) {
+	cfg := testConfig()
+	cfg.TodosCooldown = time.Minute
+	withConfig(t, cfg)
+
+	group := types.NewJID("120363000000000001", types.GroupServer)
+	mem := transport.NewMemory()
+	todos := func() {
+		mem.Reset()
+		HandleCommand(context.Background(), transport.NewMemoryConversation(mem, group, testAdmin, "!todos reunião"))
+	}
+
+	// Sem participantes nada é enviado, e o grupo não perde a vez (quem chama entra só uma vez nas menções)
+	todos()
+	if got := mem.Texts(); len(got) != 1 || got[0] != "⚠️ Nenhum participante para mencionar." {
+		t.Fatalf("respostas sem participantes = %q", got)
+	}
+
+	mem.Groups[group] = []types.JID{testUser, testAdmin, testAdmin}
+	todos()
+	sent := mem.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("%d mensagem(ns) enviada(s), quero 1", len(sent))
+	}
+	mentions := sent[0].Message.GetExtendedTextMessage().GetContextInfo().GetMentionedJID()
+	want := []string{testUser.String(), testAdmin.String()}
+	if len(mentions) != len(want) || mentions[0] != want[0] || mentions[1] != want[1] {
+		t.Errorf("menções = %v, quero %v", mentions, want)
+	}
+
+	todos()
+	if got := mem.Texts(); len(got) != 1 || !strings.HasPrefix(got[0], "⏳ Aguarde") {
+		t.Errorf("respostas dentro do cooldown = %q", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/utils"
 )
 
 type Message struct {
@@ -92,5 +93,6 @@ func AskChatGPT(prompt string) (string, error) {
 		return "🤖 A IA não respondeu nada útil.", nil
 	}
 
-	return result.Choices[0].Message.Content, nil
+	// Respostas da IA chegam em markdown; converte para a marcação do WhatsApp antes de enviar
+	return utils.MarkdownToWhatsApp(result.Choices[0].Message.Content), nil
 }
@@ -6,12 +6,13 @@ import (
 	"time"
 
 	"github.com/faysk/whatsapp-bot/services"
+	"github.com/faysk/whatsapp-bot/transport"
 	"github.com/go-co-op/gocron"
-	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 // StartDailyNews agenda o envio diário de notícias de criptomoedas às 10h (horário local)
-func StartDailyNews(ctx context.Context, client *whatsmeow.Client, numbers []string) {
+func StartDailyNews(ctx context.Context, m transport.Messenger, numbers []string) {
 	if len(numbers) == 0 {
 		log.Println("⚠️ Nenhum número autorizado para envio de notícias.")
 		return
@@ -25,7 +26,7 @@ func StartDailyNews(ctx context.Context, client *whatsmeow.Client, numbers []str
 				log.Printf("⚠️ Panic recuperado no job de notícias: %v", r)
 			}
 		}()
-		sendCryptoNews(ctx, client, numbers)
+		sendCryptoNews(ctx, m, numbers)
 	})
 
 	if err != nil {
@@ -38,7 +39,7 @@ func StartDailyNews(ctx context.Context, client *whatsmeow.Client, numbers []str
 }
 
 // sendCryptoNews busca e envia as últimas atualizações de criptomoedas em dois blocos (Trending + News)
-func sendCryptoNews(ctx context.Context, client *whatsmeow.Client, numbers []string) {
+func sendCryptoNews(ctx context.Context, m transport.Messenger, numbers []string) {
 	now := time.Now().Format("2006-01-02 15:04:05")
 	log.Printf("📡 [%s] Iniciando coleta de notícias do CryptoPanic...", now)
 
@@ -57,23 +58,23 @@ func sendCryptoNews(ctx context.Context, client *whatsmeow.Client, numbers []str
 		len(trendingMsg), len(newsMsg),
 	)
 
+	// Respeita o horário de silêncio de cada destinatário (as notícias ficam para o resumo)
 	for _, number := range numbers {
 		if number == "" {
 			continue
 		}
+		jid := types.NewJID(number, types.DefaultUserServer)
 
 		if trendingMsg != "" {
 			log.Printf("📤 Enviando 🔥 *Tópicos em Alta* para %s", number)
-			services.SendToNumber(ctx, client, number, trendingMsg)
-			time.Sleep(2 * time.Second)
+			services.SendProactive(ctx, m, jid, services.Text{Body: trendingMsg}, false)
 		}
 
 		if newsMsg != "" {
 			log.Printf("📤 Enviando 🗞️ *Últimas Notícias* para %s", number)
-			services.SendToNumber(ctx, client, number, newsMsg)
-			time.Sleep(2 * time.Second)
+			services.SendProactive(ctx, m, jid, services.Text{Body: newsMsg}, false)
 		}
 	}
 
-	log.Printf("✅ [%s] Notícias cripto enviadas com sucesso.", now)
+	log.Printf("✅ [%s] Notícias cripto encaminhadas para envio.", now)
 }
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/store"
+)
+
+// athImportKey marca que os recordes do antigo crypto_records.json (ATH_RECORDS_FILE) já foram importados
+const athImportKey = "ath_records_imported"
+
+// CryptoRecord é o formato de cada moeda no antigo crypto_records.json
+type CryptoRecord struct {
+	AllTimeHigh float64   `json:"ath"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+var (
+	// athMemory guarda os recordes quando o banco está desconectado (perdidos ao reiniciar)
+	athMemoryMu sync.Mutex
+	athMemory   = map[string]float64{}
+)
+
+// claimATH registra o novo recorde e informa se o aviso deve ser enviado
+func claimATH(ctx context.Context, symbol, id string, priceUSD float64) (bool, error) {
+	if store.DB != nil {
+		return store.ClaimATH(ctx, symbol, id, priceUSD)
+	}
+
+	athMemoryMu.Lock()
+	defer athMemoryMu.Unlock()
+	if priceUSD <= athMemory[symbol] {
+		return false, nil
+	}
+	athMemory[symbol] = priceUSD
+	return true, nil
+}
+
+// recordATHSeen grava o último preço visto de cada moeda monitorada
+func recordATHSeen(ctx context.Context, seen []store.ATHSeen) {
+	if store.DB == nil {
+		return
+	}
+	if err := store.RecordATHSeen(ctx, seen); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+}
+
+// importLegacyATHRecords copia, uma única vez, os recordes do antigo crypto_records.json (ATH_RECORDS_FILE)
+// para o banco. Recordes já existentes nunca diminuem, então um arquivo desatualizado não faz avisos
+// antigos voltarem. Enquanto o arquivo não aparece, a importação fica pendente e é tentada a cada início.
+func importLegacyATHRecords(ctx context.Context) {
+	if store.DB == nil {
+		return
+	}
+
+	_, done, err := store.GetChatSetting(ctx, store.GlobalChat, athImportKey)
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		return
+	}
+	if done {
+		return
+	}
+
+	file := config.AppConfig.ATHRecordsFile
+	if file == "" {
+		return
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("⚠️ Recordes de ATH ainda não importados: %s não encontrado — monte o arquivo antigo (ou aponte ATH_RECORDS_FILE para ele) para não repetir avisos de recordes já enviados", file)
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️ Erro ao ler %s: %v", file, err)
+		return
+	}
+
+	var legacy map[string]CryptoRecord
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		log.Printf("⚠️ Erro ao ler JSON de %s: %v", file, err)
+		return
+	}
+
+	records := make(map[string]store.ATHRecord, len(legacy))
+	for symbol, r := range legacy {
+		rec := store.ATHRecord{Symbol: symbol, ATHUSD: r.AllTimeHigh}
+		if !r.Timestamp.IsZero() {
+			at := r.Timestamp
+			rec.ATHAt = &at
+		}
+		records[symbol] = rec
+	}
+
+	if err := store.ImportATHRecords(ctx, records, athImportKey); err != nil {
+		log.Printf("⚠️ %v", err)
+		return
+	}
+	log.Printf("📥 %d recorde(s) de ATH importado(s) de %s — o arquivo não é mais usado", len(records), file)
+}
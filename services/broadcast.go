@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Broadcast envia o texto para todos os membros da lista pela fila de saída,
+// registrando cada destinatário para o relatório de entrega e leitura
+func Broadcast(ctx context.Context, listName, content, author string) (int64, int, error) {
+	if !outboxRunning.Load() {
+		return 0, 0, fmt.Errorf("⚠️ Fila de saída inativa — broadcast indisponível")
+	}
+
+	members, err := store.BroadcastMembers(ctx, listName)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(members) == 0 {
+		return 0, 0, fmt.Errorf("⚠️ A lista '%s' não tem membros", listName)
+	}
+
+	// Marcadores @número no texto viram menções reais (notificam nos grupos)
+	msg, preview, err := Text{Body: content, Mentions: transport.ExtractMentions(content)}.build(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	id, err := store.CreateBroadcast(ctx, listName, content, author)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, member := range members {
+		var outboxID int64
+
+		jid, err := types.ParseJID(member)
+		if err == nil {
+			outboxID, err = EnqueueMessage(ctx, jid, msg, preview)
+		}
+		if err != nil {
+			log.Printf("❌ [broadcast #%d] Falha ao enfileirar para %s: %v", id, member, err)
+		}
+
+		if err := store.AddBroadcastDelivery(ctx, id, member, outboxID); err != nil {
+			log.Printf("⚠️ [broadcast #%d] Erro ao registrar entrega para %s: %v", id, member, err)
+		}
+	}
+
+	log.Printf("📣 Broadcast #%d para a lista '%s' (%d destinatários) por %s", id, listName, len(members), author)
+	return id, len(members), nil
+}
+
+// HandleReceipt atualiza o estado de entrega/leitura das mensagens enviadas pela fila
+func HandleReceipt(ctx context.Context, evt *events.Receipt) {
+	if store.DB == nil || len(evt.MessageIDs) == 0 {
+		return
+	}
+
+	ids := make([]string, len(evt.MessageIDs))
+	for i, id := range evt.MessageIDs {
+		ids[i] = string(id)
+	}
+
+	var err error
+	switch evt.Type {
+	case types.ReceiptTypeDelivered:
+		err = store.MarkOutboxDelivered(ctx, ids, evt.Timestamp)
+	case types.ReceiptTypeRead, types.ReceiptTypePlayed:
+		err = store.MarkOutboxRead(ctx, ids, evt.Timestamp)
+	default:
+		return
+	}
+
+	if err != nil {
+		log.Printf("⚠️ Erro ao registrar recibo (%s) de %s: %v", evt.Type, evt.Sender.User, err)
+	}
+}
+
+// ParseRecipient interpreta um membro de lista: número (5511999999999), JID completo
+// (grupo ou contato) ou "aqui" para a conversa atual
+func ParseRecipient(input string, current types.JID) (types.JID, error) {
+	input = strings.TrimSpace(input)
+
+	switch {
+	case strings.EqualFold(input, "aqui"):
+		return current, nil
+	case strings.Contains(input, "@"):
+		jid, err := types.ParseJID(input)
+		if err != nil || (jid.Server != types.DefaultUserServer && jid.Server != types.GroupServer) {
+			return types.JID{}, fmt.Errorf("⚠️ JID inválido: %s", input)
+		}
+		return jid.ToNonAD(), nil
+	}
+
+	digits := strings.NewReplacer("+", "", "-", "", " ", "", "(", "", ")", "").Replace(input)
+	if len(digits) < 10 || len(digits) > 15 || strings.Trim(digits, "0123456789") != "" {
+		return types.JID{}, fmt.Errorf("⚠️ Número inválido: %s", input)
+	}
+	return types.NewJID(digits, types.DefaultUserServer), nil
+}
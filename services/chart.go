@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/charts"
+	"github.com/faysk/whatsapp-bot/config"
+)
+
+const (
+	marketChartAPI = "https://api.coingecko.com/api/v3/coins/%s/market_chart?vs_currency=%s&days=%d"
+	ohlcAPI        = "https://api.coingecko.com/api/v3/coins/%s/ohlc?vs_currency=%s&days=%d"
+)
+
+// chartPeriods mapeia os períodos aceitos no !grafico para os dias da API (o /ohlc só aceita estes valores)
+var chartPeriods = map[string]int{
+	"24h": 1, "1d": 1,
+	"7d": 7, "1s": 7,
+	"14d": 14,
+	"30d": 30, "1m": 30,
+	"90d": 90, "3m": 90,
+	"180d": 180, "6m": 180,
+	"1y": 365, "1a": 365, "365d": 365,
+}
+
+// ChartCaption reúne os dados da legenda enviada junto com o gráfico (template chart_caption)
+type ChartCaption struct {
+	Name      string
+	Symbol    string
+	Currency  string // BRL ou USD
+	Days      int
+	Candles   bool
+	Last      string
+	High      string
+	Low       string
+	Change    float64
+	Provider  string
+	FetchedAt time.Time
+}
+
+// chartRequest é o pedido já interpretado (ex: "btc 30d velas usd")
+type chartRequest struct {
+	coin     CoinData
+	days     int
+	currency string
+	candles  bool
+}
+
+// chartSeries guarda os dados brutos de um gráfico; é o que fica em cache
+type chartSeries struct {
+	points    []charts.Point
+	candles   []charts.Candle
+	fetchedAt time.Time
+}
+
+var (
+	chartCacheMu sync.Mutex
+	chartCache   = map[string]chartSeries{}
+	chartFlight  flightGroup[chartSeries]
+)
+
+// GetPriceChart gera o PNG do gráfico de preço e a legenda (ex: "btc 7d", "eth 30d velas usd")
+func GetPriceChart(args string) ([]byte, string, error) {
+	req, err := parseChartArgs(args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	series, err := getChartSeries(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	loc := currentLocale()
+	format := func(v float64) string { return loc.Money(v, req.currency) }
+
+	var first, last, lo, hi float64
+	if req.candles {
+		first, last = series.candles[0].Open, series.candles[len(series.candles)-1].Close
+		lo, hi = series.candles[0].Low, series.candles[0].High
+		for _, k := range series.candles {
+			lo, hi = min(lo, k.Low), max(hi, k.High)
+		}
+	} else {
+		first, last = series.points[0].Value, series.points[len(series.points)-1].Value
+		lo, hi = first, first
+		for _, p := range series.points {
+			lo, hi = min(lo, p.Value), max(hi, p.Value)
+		}
+	}
+	change := percentChange(first, last)
+	pct := loc.Percent(change, true)
+
+	opt := charts.Options{
+		Title:       fmt.Sprintf("%s/%s  %s", strings.ToUpper(req.coin.Symbol), strings.ToUpper(req.currency), chartPeriodLabel(req.days)),
+		Subtitle:    format(last) + "  " + pct,
+		FormatValue: format,
+	}
+
+	var png []byte
+	if req.candles {
+		png, err = charts.Candles(series.candles, opt)
+	} else {
+		png, err = charts.Line(series.points, opt)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Erro ao desenhar o gráfico: %w", err)
+	}
+
+	caption, err := RenderTemplate("chart_caption", ChartCaption{
+		Name:      req.coin.Name,
+		Symbol:    req.coin.Symbol,
+		Currency:  strings.ToUpper(req.currency),
+		Days:      req.days,
+		Candles:   req.candles,
+		Last:      format(last),
+		High:      format(hi),
+		Low:       format(lo),
+		Change:    change,
+		Provider:  "CoinGecko",
+		FetchedAt: series.fetchedAt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return png, caption, nil
+}
+
+// parseChartArgs interpreta moeda, período (padrão 7d), "velas" e a moeda de cotação (padrão BRL)
+func parseChartArgs(args string) (chartRequest, error) {
+	fields := strings.Fields(strings.ToLower(args))
+	if len(fields) == 0 {
+		return chartRequest{}, fmt.Errorf("⚠️ Uso: !grafico <moeda> [24h|7d|30d|90d|180d|1y] [velas] [usd] (ex: !grafico btc 7d)")
+	}
+
+	id, err := resolveCoin(fields[0])
+	if err != nil {
+		return chartRequest{}, err
+	}
+	req := chartRequest{coin: coinDir.coin(id), days: 7, currency: "brl"}
+
+	for _, f := range fields[1:] {
+		switch {
+		case f == "velas" || f == "candles" || f == "candle":
+			req.candles = true
+		case f == "linha" || f == "line":
+			req.candles = false
+		case f == "usd" || f == "brl":
+			req.currency = f
+		case chartPeriods[f] > 0:
+			req.days = chartPeriods[f]
+		default:
+			return chartRequest{}, fmt.Errorf("⚠️ Opção '%s' inválida. Períodos: 24h, 7d, 14d, 30d, 90d, 180d, 1y", f)
+		}
+	}
+	return req, nil
+}
+
+// getChartSeries busca os dados no CoinGecko, reaproveitando o cache e pedidos simultâneos iguais
+func getChartSeries(req chartRequest) (chartSeries, error) {
+	kind := "line"
+	if req.candles {
+		kind = "ohlc"
+	}
+	key := fmt.Sprintf("%s:%s:%d:%s", req.coin.ID, req.currency, req.days, kind)
+
+	chartCacheMu.Lock()
+	cached, ok := chartCache[key]
+	chartCacheMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < chartCacheTTL(req.days) {
+		return cached, nil
+	}
+
+	series, err := chartFlight.Do(key, func() (chartSeries, error) {
+		return fetchChartSeries(context.Background(), req)
+	})
+	if err != nil {
+		if ok {
+			log.Printf("⚠️ Gráfico %s: usando dados de %s (%v)", key, cached.fetchedAt.Format("15:04:05"), err)
+			return cached, nil
+		}
+		return chartSeries{}, err
+	}
+
+	chartCacheMu.Lock()
+	chartCache[key] = series
+	chartCacheMu.Unlock()
+	return series, nil
+}
+
+func fetchChartSeries(ctx context.Context, req chartRequest) (chartSeries, error) {
+	series := chartSeries{fetchedAt: time.Now()}
+	var err error
+	if req.candles {
+		var rows [][5]float64
+		err = coingeckoGet(ctx, fmt.Sprintf(ohlcAPI, req.coin.ID, req.currency, req.days), &rows)
+		for _, r := range rows {
+			series.candles = append(series.candles, charts.Candle{
+				Time: time.UnixMilli(int64(r[0])), Open: r[1], High: r[2], Low: r[3], Close: r[4],
+			})
+		}
+	} else {
+		var data struct {
+			Prices [][2]float64 `json:"prices"`
+		}
+		err = coingeckoGet(ctx, fmt.Sprintf(marketChartAPI, req.coin.ID, req.currency, req.days), &data)
+		for _, r := range data.Prices {
+			series.points = append(series.points, charts.Point{Time: time.UnixMilli(int64(r[0])), Value: r[1]})
+		}
+	}
+
+	var rl *rateLimitError
+	switch {
+	case errors.As(err, &rl):
+		return chartSeries{}, fmt.Errorf("⏳ Limite de requisições do CoinGecko atingido, tente novamente em instantes")
+	case errors.Is(err, errUnsupportedCoin):
+		return chartSeries{}, fmt.Errorf("❌ Histórico de %s indisponível no CoinGecko", strings.ToUpper(req.coin.Symbol))
+	case err != nil:
+		return chartSeries{}, err
+	}
+
+	if len(series.points) < 2 && len(series.candles) < 2 {
+		return chartSeries{}, fmt.Errorf("❌ Dados insuficientes para o gráfico de %s", strings.ToUpper(req.coin.Symbol))
+	}
+	return series, nil
+}
+
+// chartCacheTTL acompanha a granularidade da API: períodos longos mudam pouco entre consultas
+func chartCacheTTL(days int) time.Duration {
+	ttl := config.AppConfig.PriceCacheTTL
+	if days > 1 {
+		ttl *= 5
+	}
+	return ttl
+}
+
+func chartPeriodLabel(days int) string {
+	switch days {
+	case 1:
+		return "24H"
+	case 365:
+		return "1A"
+	default:
+		return fmt.Sprintf("%dD", days)
+	}
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/store"
+)
+
+const (
+	coinListAPI   = "https://api.coingecko.com/api/v3/coins/list"
+	coinRanksAPI  = "https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=250&page=%d"
+	coinRankPages = 4 // top 1000 por market cap: suficiente para desempatar os símbolos relevantes
+	coinListRetry = 10 * time.Minute
+)
+
+type CoinData struct {
+	ID     string
+	Name   string
+	Symbol string
+	Rank   int // posição por market cap; 0 = sem rank
+}
+
+type coinInfo struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// coinDirectory resolve aliases (btc, bitcoin, "bitcoin cash"...) para IDs do CoinGecko.
+// Começa só com PredefinedAliases e é trocado por inteiro a cada carga do diretório completo.
+type coinDirectory struct {
+	mu        sync.RWMutex
+	aliases   map[string]string   // alias → id
+	ambiguous map[string][]string // alias → candidatos (melhor rank primeiro) quando não há como desempatar
+	info      map[string]CoinData // id → CoinData
+	updated   time.Time
+}
+
+var coinDir = newCoinDirectory()
+
+func newCoinDirectory() *coinDirectory {
+	d := &coinDirectory{}
+	d.aliases, d.ambiguous, d.info = buildCoinMaps(nil)
+	return d
+}
+
+// lookup devolve o ID do CoinGecko para o alias informado
+func (d *coinDirectory) lookup(alias string) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	id, ok := d.aliases[alias]
+	return id, ok
+}
+
+// candidates devolve as moedas que disputam um alias ambíguo (vazio se o alias não é ambíguo)
+func (d *coinDirectory) candidates(alias string) []CoinData {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	ids := d.ambiguous[alias]
+	coins := make([]CoinData, 0, len(ids))
+	for _, id := range ids {
+		coins = append(coins, d.info[id])
+	}
+	return coins
+}
+
+// search procura o termo no símbolo, nome e ID das moedas; símbolo exato vem primeiro, depois o rank
+func (d *coinDirectory) search(term string, limit int) []CoinData {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return nil
+	}
+
+	d.mu.RLock()
+	var found []CoinData
+	for _, c := range d.info {
+		if strings.EqualFold(c.Symbol, term) || strings.Contains(strings.ToLower(c.Name), term) || strings.Contains(c.ID, term) {
+			found = append(found, c)
+		}
+	}
+	d.mu.RUnlock()
+
+	sort.Slice(found, func(i, j int) bool {
+		ei, ej := strings.EqualFold(found[i].Symbol, term), strings.EqualFold(found[j].Symbol, term)
+		if ei != ej {
+			return ei
+		}
+		return rankLess(found[i], found[j])
+	})
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	return found
+}
+
+// coin devolve os dados da moeda; sem diretório carregado, o símbolo vem dos aliases fixos
+func (d *coinDirectory) coin(id string) CoinData {
+	d.mu.RLock()
+	c, ok := d.info[id]
+	d.mu.RUnlock()
+	if ok {
+		return c
+	}
+
+	c = CoinData{ID: id, Name: id}
+	for alias, target := range PredefinedAliases {
+		if target != id || alias == id {
+			continue
+		}
+		if c.Symbol == "" || len(alias) < len(c.Symbol) || (len(alias) == len(c.Symbol) && strings.ToUpper(alias) < c.Symbol) {
+			c.Symbol = strings.ToUpper(alias)
+		}
+	}
+	return c
+}
+
+func (d *coinDirectory) replace(coins []store.CoinEntry, updated time.Time) {
+	aliases, ambiguous, info := buildCoinMaps(coins)
+
+	d.mu.Lock()
+	d.aliases, d.ambiguous, d.info, d.updated = aliases, ambiguous, info, updated
+	d.mu.Unlock()
+}
+
+// ranks devolve o rank atual de cada moeda ranqueada
+func (d *coinDirectory) ranks() map[string]int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	ranks := make(map[string]int)
+	for id, c := range d.info {
+		if c.Rank > 0 {
+			ranks[id] = c.Rank
+		}
+	}
+	return ranks
+}
+
+func (d *coinDirectory) lastUpdate() (time.Time, int) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.updated, len(d.info)
+}
+
+// buildCoinMaps monta os mapas de alias. Os aliases fixos têm prioridade, depois o ID exato da moeda;
+// símbolos e nomes repetidos ficam com a moeda de melhor rank. Sem rank para desempatar, o alias
+// fica ambíguo e o usuário escolhe entre os candidatos.
+func buildCoinMaps(coins []store.CoinEntry) (map[string]string, map[string][]string, map[string]CoinData) {
+	aliases := make(map[string]string, len(PredefinedAliases)+len(coins)*3)
+	ambiguous := make(map[string][]string)
+	info := make(map[string]CoinData, len(coins))
+
+	for alias, id := range PredefinedAliases {
+		aliases[strings.ToLower(alias)] = id
+	}
+
+	candidates := make(map[string][]string, len(coins)*3)
+	for _, coin := range coins {
+		name := strings.ToLower(coin.Name)
+
+		info[coin.ID] = CoinData{
+			ID:     coin.ID,
+			Name:   coin.Name,
+			Symbol: strings.ToUpper(coin.Symbol),
+			Rank:   coin.Rank,
+		}
+
+		if _, exists := aliases[coin.ID]; !exists {
+			aliases[coin.ID] = coin.ID
+		}
+
+		for _, alias := range uniqueStrings(strings.ToLower(coin.Symbol), name, strings.ReplaceAll(name, " ", "")) {
+			candidates[alias] = append(candidates[alias], coin.ID)
+		}
+	}
+
+	for alias, ids := range candidates {
+		if _, exists := aliases[alias]; exists {
+			continue
+		}
+		if len(ids) == 1 {
+			aliases[alias] = ids[0]
+			continue
+		}
+
+		sort.Slice(ids, func(i, j int) bool { return rankLess(info[ids[i]], info[ids[j]]) })
+		if info[ids[0]].Rank > 0 {
+			aliases[alias] = ids[0]
+		} else {
+			ambiguous[alias] = ids
+		}
+	}
+	return aliases, ambiguous, info
+}
+
+// rankLess ordena por rank de market cap (moedas sem rank por último) e depois pelo ID
+func rankLess(a, b CoinData) bool {
+	if (a.Rank == 0) != (b.Rank == 0) {
+		return a.Rank != 0
+	}
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.ID < b.ID
+}
+
+func uniqueStrings(values ...string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != "" && !containsString(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// StartCoinDirectory carrega o diretório de moedas salvo no banco e o mantém atualizado em segundo plano.
+// Até a primeira carga, apenas os aliases fixos (PredefinedAliases) são reconhecidos.
+func StartCoinDirectory(ctx context.Context) {
+	if store.DB != nil {
+		coins, updated, err := store.LoadCoins(ctx)
+		switch {
+		case err != nil:
+			log.Printf("⚠️ %v", err)
+		case len(coins) > 0:
+			coinDir.replace(coins, updated)
+			log.Printf("🪙 %d criptomoedas carregadas do banco (atualizadas em %s).", len(coins), updated.Local().Format("02/01/2006 15:04"))
+		}
+	}
+
+	interval := config.AppConfig.CoinListRefresh
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("🔥 Panic recuperado na atualização do diretório de moedas: %v", r)
+			}
+		}()
+
+		// Diretório vencido (ou nunca baixado): atualiza já; falhas tentam de novo mais cedo
+		wait := time.Duration(0)
+		if updated, _ := coinDir.lastUpdate(); time.Since(updated) < interval {
+			wait = interval - time.Since(updated)
+		}
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			if refreshCoinDirectory(ctx) {
+				timer.Reset(interval)
+			} else {
+				timer.Reset(coinListRetry)
+			}
+		}
+	}()
+}
+
+// refreshCoinDirectory baixa /coins/list, troca o diretório em memória e persiste a nova versão
+func refreshCoinDirectory(ctx context.Context) bool {
+	log.Println("🔄 Atualizando diretório de criptomoedas...")
+
+	coins, err := fetchCoinList(ctx)
+	if err != nil {
+		// Mantém o último diretório conhecido
+		log.Printf("⚠️ %v", err)
+		return false
+	}
+
+	ranks, err := fetchCoinRanks(ctx)
+	if err != nil {
+		// Sem o ranking novo, aproveita o da carga anterior para continuar desempatando os símbolos
+		log.Printf("⚠️ Ranking de market cap indisponível, mantendo o anterior: %v", err)
+		ranks = coinDir.ranks()
+	}
+	for i := range coins {
+		coins[i].Rank = ranks[coins[i].ID]
+	}
+
+	coinDir.replace(coins, time.Now())
+	log.Printf("✅ %d criptomoedas carregadas de CoinGecko.", len(coins))
+
+	if store.DB != nil {
+		if err := store.ReplaceCoins(ctx, coins); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+	}
+	return true
+}
+
+func fetchCoinList(ctx context.Context) ([]store.CoinEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, coinListAPI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("🌐 Erro ao acessar CoinGecko: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("❌ CoinGecko retornou status %d ao listar moedas", resp.StatusCode)
+	}
+
+	var list []coinInfo
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("⚠️ Erro ao decodificar resposta do CoinGecko: %w", err)
+	}
+
+	coins := make([]store.CoinEntry, 0, len(list))
+	for _, c := range list {
+		coins = append(coins, store.CoinEntry{ID: c.ID, Symbol: c.Symbol, Name: c.Name})
+	}
+	return coins, nil
+}
+
+// fetchCoinRanks consulta as primeiras páginas de /coins/markets para saber o rank de cada moeda
+func fetchCoinRanks(ctx context.Context) (map[string]int, error) {
+	ranks := make(map[string]int, coinRankPages*250)
+	for page := 1; page <= coinRankPages; page++ {
+		var rows []struct {
+			ID            string `json:"id"`
+			MarketCapRank int    `json:"market_cap_rank"`
+		}
+		if err := coingeckoGet(ctx, fmt.Sprintf(coinRanksAPI, page), &rows); err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			if r.MarketCapRank > 0 {
+				ranks[r.ID] = r.MarketCapRank
+			}
+		}
+	}
+	return ranks, nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+const maxCoinChoices = 8
+
+// AmbiguousCoinError indica que o alias corresponde a várias moedas sem rank para desempatar;
+// quem recebe deve pedir que o usuário escolha um dos candidatos.
+type AmbiguousCoinError struct {
+	Alias      string
+	Candidates []CoinData
+}
+
+func (e *AmbiguousCoinError) Error() string {
+	msg, err := RenderCoinChoices(e.Alias, e.Candidates, true)
+	if err != nil {
+		return fmt.Sprintf("🤔 '%s' corresponde a %d moedas. Use !buscar %s", e.Alias, len(e.Candidates), e.Alias)
+	}
+	return msg
+}
+
+// CoinChoices reúne os dados do template coin_choices (resultado do !buscar ou pedido de escolha)
+type CoinChoices struct {
+	Term  string
+	Coins []CoinData
+	Ask   bool // true quando o bot pede para o usuário responder com o número
+	More  int  // candidatos além dos exibidos
+}
+
+// resolveCoin traduz o alias digitado para o ID do CoinGecko; aliases ambíguos devolvem *AmbiguousCoinError
+func resolveCoin(input string) (string, error) {
+	alias := strings.ToLower(strings.TrimSpace(input))
+	if id, ok := coinDir.lookup(alias); ok {
+		return id, nil
+	}
+	if candidates := coinDir.candidates(alias); len(candidates) > 0 {
+		if len(candidates) > maxCoinChoices {
+			candidates = candidates[:maxCoinChoices]
+		}
+		return "", &AmbiguousCoinError{Alias: alias, Candidates: candidates}
+	}
+	return "", fmt.Errorf("❌ Criptomoeda '%s' não reconhecida", input)
+}
+
+// SearchCoins lista as moedas cujo símbolo, nome ou ID contém o termo (ex: !buscar pepe)
+func SearchCoins(term string) (string, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return "", fmt.Errorf("⚠️ Uso: !buscar <termo> (ex: !buscar pepe)")
+	}
+
+	coins := coinDir.search(term, maxCoinChoices+1)
+	if len(coins) == 0 {
+		return "", fmt.Errorf("🔍 Nenhuma moeda encontrada para '%s'", term)
+	}
+	return RenderCoinChoices(term, coins, false)
+}
+
+// RenderCoinChoices monta a lista numerada de moedas; com ask, pede que o usuário responda com o número
+func RenderCoinChoices(term string, coins []CoinData, ask bool) (string, error) {
+	data := CoinChoices{Term: term, Coins: coins, Ask: ask}
+	if len(coins) > maxCoinChoices {
+		data.Coins, data.More = coins[:maxCoinChoices], len(coins)-maxCoinChoices
+	}
+	return RenderTemplate("coin_choices", data)
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/utils"
+)
+
+// Conversion reúne os dados exibidos na resposta do !converter (template conversion)
+type Conversion struct {
+	Amount    float64
+	From      string
+	Result    float64
+	To        string
+	Rate      float64 // quanto 1 unidade de From vale em To
+	Sources   string
+	FetchedAt time.Time
+}
+
+// convLeg é um dos lados da conversão, expresso em dólares
+type convLeg struct {
+	code     string
+	usd      float64 // valor de 1 unidade em USD
+	provider string
+	at       time.Time
+}
+
+// conversionFillers são palavras aceitas entre os termos (ex: "0,05 btc para brl")
+var conversionFillers = map[string]bool{"para": true, "em": true, "to": true, "in": true, "->": true, "=": true}
+
+// Convert converte valores entre cripto e moedas fiduciárias (ex: "0,05 btc brl", "500 reais sol", "100 usd eur")
+func Convert(args string) (string, error) {
+	var terms []string
+	for _, f := range strings.Fields(args) {
+		if !conversionFillers[strings.ToLower(f)] {
+			terms = append(terms, f)
+		}
+	}
+	if len(terms) != 3 {
+		return "", fmt.Errorf("⚠️ Uso: !converter <valor> <de> <para> (ex: !converter 0,05 btc brl)")
+	}
+
+	amount, err := utils.ParseAmount(terms[0])
+	if err != nil {
+		return "", err
+	}
+	if amount <= 0 {
+		return "", fmt.Errorf("⚠️ O valor precisa ser maior que zero")
+	}
+
+	ctx := context.Background()
+	rates, ratesErr := GetFiatRates(ctx)
+
+	from, err := resolveConvLeg(terms[1], rates, ratesErr)
+	if err != nil {
+		return "", err
+	}
+	to, err := resolveConvLeg(terms[2], rates, ratesErr)
+	if err != nil {
+		return "", err
+	}
+
+	rate := from.usd / to.usd
+	sources := from.provider
+	if to.provider != from.provider {
+		sources += ", " + to.provider
+	}
+	at := from.at
+	if to.at.Before(at) {
+		at = to.at
+	}
+
+	return RenderTemplate("conversion", Conversion{
+		Amount:    amount,
+		From:      from.code,
+		Result:    amount * rate,
+		To:        to.code,
+		Rate:      rate,
+		Sources:   sources,
+		FetchedAt: at,
+	})
+}
+
+// resolveConvLeg identifica o termo como moeda fiduciária (prioridade) ou criptomoeda
+func resolveConvLeg(input string, rates FiatRates, ratesErr error) (convLeg, error) {
+	if code, ok := rates.resolveFiat(input); ok {
+		return convLeg{
+			code:     strings.ToUpper(code),
+			usd:      1 / rates.PerUSD[code],
+			provider: rates.Provider,
+			at:       rates.FetchedAt,
+		}, nil
+	}
+
+	id, err := resolveCoin(input)
+	var ambiguous *AmbiguousCoinError
+	switch {
+	case errors.As(err, &ambiguous):
+		return convLeg{}, err
+	case err != nil && ratesErr != nil:
+		return convLeg{}, ratesErr
+	case err != nil:
+		return convLeg{}, fmt.Errorf("❌ Moeda '%s' não reconhecida", input)
+	}
+
+	q, err := GetMarketQuote(id)
+	if err != nil {
+		return convLeg{}, err
+	}
+	if q.PriceUSD <= 0 {
+		return convLeg{}, fmt.Errorf("❌ %s sem cotação em dólar no momento", strings.ToUpper(q.Symbol))
+	}
+
+	return convLeg{
+		code:     strings.ToUpper(q.Symbol),
+		usd:      q.PriceUSD,
+		provider: q.Provider,
+		at:       q.FetchedAt,
+	}, nil
+}
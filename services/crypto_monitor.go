@@ -1,31 +1,24 @@
 package services
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
-	"net/http"
-	"os"
 	"strings"
 	"time"
-)
-
-type CryptoRecord struct {
-	AllTimeHigh float64   `json:"ath"`
-	Timestamp   time.Time `json:"timestamp"`
-}
-
-var monitoredCoins = []string{"BTC", "ETH", "USDT", "XRP", "SOL"}
 
-const (
-	coinDetailAPI  = "https://api.coingecko.com/api/v3/coins/%s?localization=false&tickers=false&market_data=true&community_data=false&developer_data=false&sparkline=false"
-	recordFilePath = "crypto_records.json"
-	checkInterval  = 5 * time.Minute
-	requestTimeout = 10 * time.Second
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
 )
 
-func MonitorCryptos(sendAlert func(string)) {
-	ticker := time.NewTicker(checkInterval)
+// MonitorCryptos verifica periodicamente os alertas de preço dos usuários (avisados na conversa
+// em que foram criados) e as máximas históricas das moedas monitoradas (avisadas às conversas que
+// assinam a moeda, via !assinar ath). Moedas e intervalo vêm de MONITORED_COINS/MONITOR_INTERVAL
+// e podem ser trocados com !monitor.
+func MonitorCryptos(ctx context.Context, m transport.Messenger) {
+	loadMonitorSettings(ctx)
+	importLegacyATHRecords(ctx)
+	seedSubscriptions(ctx)
 
 	go func() {
 		defer func() {
@@ -34,104 +27,155 @@ func MonitorCryptos(sendAlert func(string)) {
 			}
 		}()
 
-		records := loadRecords()
-		client := &http.Client{Timeout: requestTimeout}
-
-		for range ticker.C {
-			log.Println("🔍 Verificando máximas históricas (ATH oficiais)...")
-
-			for _, symbol := range monitoredCoins {
-				id := ResolveAlias(symbol)
-				url := fmt.Sprintf(coinDetailAPI, id)
-
-				resp, err := client.Get(url)
-				if err != nil {
-					log.Printf("❌ [%s] erro HTTP ao acessar CoinGecko: %v", symbol, err)
-					continue
-				}
-
-				func() {
-					defer resp.Body.Close()
-
-					if resp.StatusCode != http.StatusOK {
-						log.Printf("❌ [%s] CoinGecko retornou status %d", symbol, resp.StatusCode)
-						return
+		timer := time.NewTimer(MonitorInterval())
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-monitorReset:
+				// Intervalo alterado via !monitor: a próxima verificação já segue o novo valor
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
 					}
+				}
+				timer.Reset(MonitorInterval())
+				continue
+			case <-timer.C:
+			}
 
-					var data struct {
-						MarketData struct {
-							CurrentPrice map[string]float64 `json:"current_price"`
-							ATH          map[string]float64 `json:"ath"`
-						} `json:"market_data"`
-					}
+			monitorTick(ctx, m)
+			timer.Reset(MonitorInterval())
+		}
+	}()
+}
 
-					if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-						log.Printf("❌ [%s] erro ao decodificar resposta: %v", symbol, err)
-						return
-					}
+// monitorTick busca, em uma única consulta em lote, as moedas monitoradas e as dos alertas de
+// preço, e repassa as mesmas cotações às duas verificações
+func monitorTick(ctx context.Context, m transport.Messenger) {
+	monitored := monitoredCoinIDs()
 
-					current := data.MarketData.CurrentPrice["usd"]
-					ath := data.MarketData.ATH["usd"]
-					key := strings.ToUpper(symbol)
-					last := records[key]
+	var alerts []store.PriceAlert
+	if store.DB != nil {
+		var err error
+		if alerts, err = store.AllPriceAlerts(ctx); err != nil {
+			log.Printf("⚠️ Alertas de preço: %v", err)
+		}
+	}
 
-					if current > ath && current > last.AllTimeHigh {
-						log.Printf("🚀 [%s] quebrou o recorde histórico oficial! $%.2f > ATH $%.2f", symbol, current, ath)
+	ids := append([]string(nil), monitored...)
+	for _, a := range alerts {
+		if !containsString(ids, a.CoinID) {
+			ids = append(ids, a.CoinID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
 
-						msg := GetCryptoPriceMessage(symbol, current, ath)
+	quotes, err := GetMarketsBatch(ids)
+	if err != nil {
+		log.Printf("❌ Monitor de criptos sem cotações: %v", err)
+		return
+	}
+	byID := make(map[string]MarketQuote, len(quotes))
+	for _, q := range quotes {
+		byID[q.ID] = q
+		priceWindows.record(q.ID, q.PriceUSD, q.FetchedAt)
+	}
 
-						alert := fmt.Sprintf("🚨 *NOVO RECORD HISTÓRICO (ATH)*\n\n%s\n\n🕒 ATH superado em %s", msg, time.Now().Format("02/01/2006 15:04"))
-						sendAlert(alert)
+	checkPriceAlerts(ctx, m, alerts, byID)
 
-						records[key] = CryptoRecord{
-							AllTimeHigh: current,
-							Timestamp:   time.Now(),
-						}
-						saveRecords(records)
-					} else {
-						log.Printf("ℹ️ [%s] U$ %.2f — abaixo do ATH oficial U$ %.2f", symbol, current, ath)
-					}
-				}()
-			}
+	monitoredQuotes := make([]MarketQuote, 0, len(monitored))
+	for _, id := range monitored {
+		if q, ok := byID[id]; ok {
+			monitoredQuotes = append(monitoredQuotes, q)
 		}
-	}()
-}
-
-// ResolveAlias converte "BTC" em "bitcoin" usando o mapa PredefinedAliases
-func ResolveAlias(symbol string) string {
-	alias := strings.ToLower(strings.TrimSpace(symbol))
-	if id, ok := PredefinedAliases[alias]; ok {
-		return id
 	}
-	return alias
+	checkATHs(ctx, m, monitoredQuotes)
 }
 
-func loadRecords() map[string]CryptoRecord {
-	data, err := os.ReadFile(recordFilePath)
-	if err != nil {
-		log.Println("📂 Nenhum histórico salvo, iniciando novo...")
-		return make(map[string]CryptoRecord)
-	}
-	var records map[string]CryptoRecord
-	if err := json.Unmarshal(data, &records); err != nil {
-		log.Printf("⚠️ Erro ao ler JSON: %v", err)
-		return make(map[string]CryptoRecord)
+// monitoredCoinIDs resolve as moedas monitoradas (MONITORED_COINS ou !monitor) para IDs, sem repetições
+func monitoredCoinIDs() []string {
+	var ids []string
+	for _, alias := range MonitoredCoins() {
+		id, err := resolveCoin(alias)
+		if err != nil {
+			log.Printf("⚠️ [%s] moeda monitorada ignorada: %v", alias, err)
+			continue
+		}
+		if !containsString(ids, id) {
+			ids = append(ids, id)
+		}
 	}
-	return records
+	return ids
 }
 
-func saveRecords(records map[string]CryptoRecord) {
-	data, err := json.MarshalIndent(records, "", "  ")
-	if err != nil {
-		log.Printf("❌ Erro ao codificar JSON: %v", err)
+// checkATHs compara as cotações das moedas monitoradas com o ATH oficial.
+// O recorde avisado e o último preço de cada moeda ficam no banco (bot_ath_records).
+func checkATHs(ctx context.Context, m transport.Messenger, quotes []MarketQuote) {
+	if len(quotes) == 0 {
 		return
 	}
-	if err := os.WriteFile(recordFilePath, data, 0644); err != nil {
-		log.Printf("❌ Erro ao salvar JSON: %v", err)
+	log.Println("🔍 Verificando máximas históricas (ATH oficiais)...")
+
+	seen := make([]store.ATHSeen, 0, len(quotes))
+	for _, q := range quotes {
+		seen = append(seen, store.ATHSeen{Symbol: strings.ToUpper(q.Symbol), CoinID: q.ID, PriceUSD: q.PriceUSD, At: q.FetchedAt})
+	}
+	recordATHSeen(ctx, seen)
+
+	for _, q := range quotes {
+		symbol := strings.ToUpper(q.Symbol)
+
+		// Só o CoinGecko informa o ATH oficial; cotações de corretoras não servem para comparar
+		if q.ATHUSD <= 0 {
+			log.Printf("ℹ️ [%s] %s não informa ATH — verificação adiada", symbol, q.Provider)
+			continue
+		}
+
+		current := q.PriceUSD
+		ath := q.ATHUSD
+		if current <= ath {
+			log.Printf("ℹ️ [%s] U$ %.2f — abaixo do ATH oficial U$ %.2f", symbol, current, ath)
+			continue
+		}
+
+		// O recorde é registrado antes do aviso: se outro ciclo (ou instância) já avisou este preço, não repete
+		claimed, err := claimATH(ctx, symbol, q.ID, current)
+		if err != nil {
+			log.Printf("⚠️ [%s] %v", symbol, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		log.Printf("🚀 [%s] quebrou o recorde histórico oficial! $%.2f > ATH $%.2f", symbol, current, ath)
+
+		alert, err := RenderTemplate("ath_alert", struct {
+			Price string
+			At    time.Time
+		}{GetCryptoPriceMessage(symbol, current, ath), time.Now()})
+		if err != nil {
+			log.Printf("⚠️ [%s] %v", symbol, err)
+			continue
+		}
+		notifySubscribers(ctx, m, EventATH, q.ID, alert)
 	}
 }
 
 // GetCryptoPriceMessage é um fallback caso não use OpenAI
 func GetCryptoPriceMessage(symbol string, current float64, ath float64) string {
-	return fmt.Sprintf("*%s*: preço atual U$ %.2f (ATH anterior: U$ %.2f)", strings.ToUpper(symbol), current, ath)
+	msg, err := RenderTemplate("ath_price", struct {
+		Symbol       string
+		Current, ATH float64
+	}{symbol, current, ath})
+	if err != nil {
+		loc := currentLocale()
+		return fmt.Sprintf("*%s*: %s (ATH: %s)", strings.ToUpper(symbol), loc.Money(current, "USD"), loc.Money(ath, "USD"))
+	}
+	return msg
 }
@@ -2,148 +2,59 @@
 package services
 
 import (
-	"encoding/json"
-	"fmt"
-	"log"
 	"net/http"
-	"strings"
 	"time"
 )
 
-type CoinData struct {
-	ID     string
-	Name   string
-	Symbol string
+// PriceCard reúne os dados exibidos no card de cotação (template crypto_price)
+type PriceCard struct {
+	Name         string
+	Symbol       string
+	Rank         int
+	PriceBRL     float64
+	PriceUSD     float64
+	Change1h     float64
+	Change24h    float64
+	NoChange24h  bool
+	Change7d     float64
+	Change30d    float64
+	Change1y     float64
+	MarketCapBRL float64
+	VolumeBRL    float64
+	Provider     string
+	FetchedAt    time.Time
 }
 
-type coinInfo struct {
-	ID     string `json:"id"`
-	Symbol string `json:"symbol"`
-	Name   string `json:"name"`
-}
-
-var (
-	cryptoAliases = make(map[string]string)   // alias → id
-	cryptoInfoMap = make(map[string]CoinData) // id → CoinData
-	client        = &http.Client{Timeout: 10 * time.Second}
-)
-
-func init() {
-	log.Println("🔄 Carregando aliases de criptomoedas...")
-
-	// Adiciona aliases fixos
-	for alias, id := range PredefinedAliases {
-		cryptoAliases[strings.ToLower(alias)] = id
-	}
-
-	// Consulta CoinGecko
-	resp, err := client.Get("https://api.coingecko.com/api/v3/coins/list")
-	if err != nil {
-		log.Printf("⚠️ Erro ao acessar CoinGecko: %v", err)
-		return
-	}
-	defer resp.Body.Close()
-
-	var coins []coinInfo
-	if err := json.NewDecoder(resp.Body).Decode(&coins); err != nil {
-		log.Printf("⚠️ Erro ao decodificar resposta do CoinGecko: %v", err)
-		return
-	}
-
-	for _, coin := range coins {
-		id := coin.ID
-		name := strings.ToLower(coin.Name)
-		symbol := strings.ToLower(coin.Symbol)
-
-		cryptoInfoMap[id] = CoinData{
-			ID:     id,
-			Name:   coin.Name,
-			Symbol: strings.ToUpper(coin.Symbol),
-		}
-
-		// Evita sobrescrever aliases manuais
-		addAliasIfMissing(id, id)
-		addAliasIfMissing(symbol, id)
-		addAliasIfMissing(name, id)
-		addAliasIfMissing(strings.ReplaceAll(name, " ", ""), id)
-	}
-
-	log.Printf("✅ %d criptomoedas carregadas de CoinGecko.", len(cryptoInfoMap))
-}
-
-func addAliasIfMissing(alias, id string) {
-	if _, exists := cryptoAliases[alias]; !exists {
-		cryptoAliases[alias] = id
-	}
-}
+// client é o HTTP client compartilhado pelas consultas de mercado
+var client = &http.Client{Timeout: 10 * time.Second}
 
-// GetCryptoPrice retorna a cotação formatada de uma moeda
+// GetCryptoPrice retorna a cotação formatada de uma moeda (via cache compartilhado de cotações)
 func GetCryptoPrice(input string) (string, error) {
-	alias := strings.ToLower(strings.TrimSpace(input))
-	cryptoID, ok := cryptoAliases[alias]
-	if !ok {
-		return "", fmt.Errorf("❌ Criptomoeda '%s' não reconhecida", input)
-	}
-
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/%s?localization=false&tickers=false&market_data=true&community_data=false&developer_data=false&sparkline=false", cryptoID)
-
-	resp, err := client.Get(url)
+	cryptoID, err := resolveCoin(input)
 	if err != nil {
-		return "", fmt.Errorf("🌐 Erro HTTP ao acessar CoinGecko: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("❌ CoinGecko retornou status %d", resp.StatusCode)
-	}
-
-	var data struct {
-		Name       string `json:"name"`
-		Symbol     string `json:"symbol"`
-		MarketData struct {
-			CurrentPrice             map[string]float64 `json:"current_price"`
-			MarketCap                map[string]float64 `json:"market_cap"`
-			TotalVolume              map[string]float64 `json:"total_volume"`
-			MarketCapRank            int                `json:"market_cap_rank"`
-			PriceChangePercentage1h  map[string]float64 `json:"price_change_percentage_1h_in_currency"`
-			PriceChangePercentage24h float64            `json:"price_change_percentage_24h"`
-			PriceChangePercentage7d  float64            `json:"price_change_percentage_7d"`
-			PriceChangePercentage30d float64            `json:"price_change_percentage_30d"`
-			PriceChangePercentage1y  float64            `json:"price_change_percentage_1y"`
-		} `json:"market_data"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", fmt.Errorf("📦 Erro ao decodificar resposta: %w", err)
-	}
-
-	formatVar := func(val float64) string {
-		switch {
-		case val > 0:
-			return fmt.Sprintf("🟢 %.2f%%", val)
-		case val < 0:
-			return fmt.Sprintf("🔴 %.2f%%", val)
-		default:
-			return fmt.Sprintf("⚪ %.2f%%", val)
-		}
+		return "", err
 	}
 
-	return fmt.Sprintf(
-		"🪙 *%s (%s)*  |  🏅 Rank: #%d\n\n"+
-			"💵 *Preço Atual*\n🇧🇷 R$ %s\n🇺🇸 $ %s\n\n"+
-			"📊 *Variação*\n1h: %s\n24h: %s\n7d: %s\n30d: %s\n1y: %s\n\n"+
-			"💰 *Market Cap:* R$ %s\n📈 *Volume 24h:* R$ %s",
-		data.Name,
-		strings.ToUpper(data.Symbol),
-		data.MarketData.MarketCapRank,
-		formatNumberBR(data.MarketData.CurrentPrice["brl"]),
-		formatNumberUS(data.MarketData.CurrentPrice["usd"]),
-		formatVar(data.MarketData.PriceChangePercentage1h["brl"]),
-		formatVar(data.MarketData.PriceChangePercentage24h),
-		formatVar(data.MarketData.PriceChangePercentage7d),
-		formatVar(data.MarketData.PriceChangePercentage30d),
-		formatVar(data.MarketData.PriceChangePercentage1y),
-		formatNumberBR(data.MarketData.MarketCap["brl"]),
-		formatNumberBR(data.MarketData.TotalVolume["brl"]),
-	), nil
+	q, err := GetMarketQuote(cryptoID)
+	if err != nil {
+		return "", err
+	}
+
+	return RenderTemplate("crypto_price", PriceCard{
+		Name:         q.Name,
+		Symbol:       q.Symbol,
+		Rank:         q.Rank,
+		PriceBRL:     q.PriceBRL,
+		PriceUSD:     q.PriceUSD,
+		Change1h:     q.Change1h,
+		Change24h:    q.Change24h,
+		NoChange24h:  q.NoChange24h,
+		Change7d:     q.Change7d,
+		Change30d:    q.Change30d,
+		Change1y:     q.Change1y,
+		MarketCapBRL: q.MarketCapBRL,
+		VolumeBRL:    q.VolumeBRL,
+		Provider:     q.Provider,
+		FetchedAt:    q.FetchedAt,
+	})
 }
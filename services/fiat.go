@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+)
+
+// FiatRates guarda quantas unidades de cada moeda fiduciária valem 1 dólar
+type FiatRates struct {
+	PerUSD    map[string]float64 // código minúsculo (brl, eur...) → unidades por USD
+	Provider  string
+	FetchedAt time.Time
+}
+
+// fiatAliases aceita nomes por extenso além dos códigos ISO
+var fiatAliases = map[string]string{
+	"real":    "brl",
+	"reais":   "brl",
+	"r$":      "brl",
+	"dolar":   "usd",
+	"dólar":   "usd",
+	"dolares": "usd",
+	"dólares": "usd",
+	"us$":     "usd",
+	"euro":    "eur",
+	"euros":   "eur",
+	"libra":   "gbp",
+	"libras":  "gbp",
+	"iene":    "jpy",
+	"ienes":   "jpy",
+}
+
+var (
+	fiatMu     sync.RWMutex
+	fiatCache  FiatRates
+	fiatFlight flightGroup[FiatRates]
+)
+
+// GetFiatRates devolve as cotações das moedas fiduciárias (CoinGecko, com Coinbase de reserva),
+// em cache por PRICE_CACHE_TTL
+func GetFiatRates(ctx context.Context) (FiatRates, error) {
+	fiatMu.RLock()
+	cached := fiatCache
+	fiatMu.RUnlock()
+
+	if cached.PerUSD != nil && time.Since(cached.FetchedAt) < config.AppConfig.PriceCacheTTL {
+		return cached, nil
+	}
+
+	rates, err := fiatFlight.Do("fiat", func() (FiatRates, error) {
+		r, err := fetchCoinGeckoFiat(ctx)
+		if err != nil {
+			log.Printf("⚠️ CoinGecko sem câmbio, tentando Coinbase: %v", err)
+			if r, err = fetchCoinbaseFiat(ctx); err != nil {
+				return FiatRates{}, err
+			}
+		}
+
+		fiatMu.Lock()
+		fiatCache = r
+		fiatMu.Unlock()
+		return r, nil
+	})
+	if err != nil {
+		if cached.PerUSD != nil {
+			return cached, nil
+		}
+		return FiatRates{}, fmt.Errorf("❌ Câmbio indisponível no momento: %w", err)
+	}
+	return rates, nil
+}
+
+// resolveFiat devolve o código da moeda fiduciária, se o texto for uma
+func (r FiatRates) resolveFiat(input string) (string, bool) {
+	code := strings.ToLower(strings.TrimSpace(input))
+	if alias, ok := fiatAliases[code]; ok {
+		code = alias
+	}
+	_, ok := r.PerUSD[code]
+	return code, ok
+}
+
+// fetchCoinGeckoFiat usa /exchange_rates, que cota tudo em BTC; convertemos para base USD
+func fetchCoinGeckoFiat(ctx context.Context) (FiatRates, error) {
+	var data struct {
+		Rates map[string]struct {
+			Value float64 `json:"value"`
+			Type  string  `json:"type"`
+		} `json:"rates"`
+	}
+	if err := getJSON(ctx, "CoinGecko", "https://api.coingecko.com/api/v3/exchange_rates", &data); err != nil {
+		return FiatRates{}, err
+	}
+
+	usd := data.Rates["usd"].Value
+	if usd <= 0 {
+		return FiatRates{}, fmt.Errorf("❌ CoinGecko não informou a cotação do dólar")
+	}
+
+	perUSD := map[string]float64{}
+	for code, r := range data.Rates {
+		if r.Type == "fiat" && r.Value > 0 {
+			perUSD[code] = r.Value / usd
+		}
+	}
+	return FiatRates{PerUSD: perUSD, Provider: "CoinGecko", FetchedAt: time.Now()}, nil
+}
+
+// fetchCoinbaseFiat usa /exchange-rates?currency=USD; a resposta mistura cripto e fiat,
+// então mantemos apenas os códigos ISO de três letras sem alias de criptomoeda
+func fetchCoinbaseFiat(ctx context.Context) (FiatRates, error) {
+	var data struct {
+		Data struct {
+			Rates map[string]string `json:"rates"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, "Coinbase", "https://api.coinbase.com/v2/exchange-rates?currency=USD", &data); err != nil {
+		return FiatRates{}, err
+	}
+
+	perUSD := map[string]float64{}
+	for code, raw := range data.Data.Rates {
+		code = strings.ToLower(code)
+		if len(code) != 3 {
+			continue
+		}
+		if _, isCoin := PredefinedAliases[code]; isCoin {
+			continue
+		}
+		if v := parseFloat(raw); v > 0 {
+			perUSD[code] = v
+		}
+	}
+	if _, ok := perUSD["brl"]; !ok {
+		return FiatRates{}, fmt.Errorf("❌ Coinbase não informou a cotação do real")
+	}
+	return FiatRates{PerUSD: perUSD, Provider: "Coinbase", FetchedAt: time.Now()}, nil
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/utils"
+)
+
+const (
+	coinHistoryAPI = "https://api.coingecko.com/api/v3/coins/%s/history?date=%s&localization=false"
+	chartRangeAPI  = "https://api.coingecko.com/api/v3/coins/%s/market_chart/range?vs_currency=%s&from=%d&to=%d"
+)
+
+// PriceHistory reúne os dados do template price_history
+type PriceHistory struct {
+	Name      string
+	Symbol    string
+	Date      time.Time
+	ThenBRL   float64
+	ThenUSD   float64
+	NowBRL    float64
+	NowUSD    float64
+	ChangeBRL float64
+	ChangeUSD float64
+	Provider  string
+	FetchedAt time.Time
+}
+
+// historicalPrice é o preço de uma moeda em um dia; dias passados não mudam e ficam em cache
+type historicalPrice struct {
+	brl, usd float64
+}
+
+var (
+	historyCacheMu sync.RWMutex
+	historyCache   = map[string]historicalPrice{} // "id|2006-01-02" → preço
+	historyFlight  flightGroup[historicalPrice]
+)
+
+// GetPriceHistory compara o preço de uma data passada com o atual (ex: "btc 15/01/2024", "eth em ontem")
+func GetPriceHistory(args string) (string, error) {
+	alias, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	rest = strings.TrimSpace(rest)
+	if r, ok := strings.CutPrefix(strings.ToLower(rest), "em "); ok {
+		rest = r
+	}
+	if alias == "" || rest == "" {
+		return "", fmt.Errorf("⚠️ Uso: !historico <moeda> <data> (ex: !historico btc 15/01/2024, !btc em ontem)")
+	}
+
+	id, err := resolveCoin(alias)
+	if err != nil {
+		return "", err
+	}
+
+	date, err := utils.ParseDate(rest, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	then, err := getHistoricalPrice(id, date)
+	if err != nil {
+		return "", err
+	}
+
+	now, err := GetMarketQuote(id)
+	if err != nil {
+		return "", err
+	}
+
+	coin := coinDir.coin(id)
+	return RenderTemplate("price_history", PriceHistory{
+		Name:      coin.Name,
+		Symbol:    coin.Symbol,
+		Date:      date,
+		ThenBRL:   then.brl,
+		ThenUSD:   then.usd,
+		NowBRL:    now.PriceBRL,
+		NowUSD:    now.PriceUSD,
+		ChangeBRL: percentChange(then.brl, now.PriceBRL),
+		ChangeUSD: percentChange(then.usd, now.PriceUSD),
+		Provider:  now.Provider,
+		FetchedAt: now.FetchedAt,
+	})
+}
+
+// getHistoricalPrice usa /coins/{id}/history e, se o dia vier sem dados, /market_chart/range
+func getHistoricalPrice(id string, date time.Time) (historicalPrice, error) {
+	key := id + "|" + date.Format("2006-01-02")
+	past := time.Since(date) > 48*time.Hour // o dia já terminou em qualquer fuso
+
+	historyCacheMu.RLock()
+	cached, ok := historyCache[key]
+	historyCacheMu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	price, err := historyFlight.Do(key, func() (historicalPrice, error) {
+		ctx := context.Background()
+		price, err := fetchHistorySnapshot(ctx, id, date)
+		if err == nil && price.brl > 0 && price.usd > 0 {
+			return price, nil
+		}
+		var rl *rateLimitError
+		if errors.As(err, &rl) {
+			return historicalPrice{}, err
+		}
+		if err != nil {
+			log.Printf("⚠️ /history de %s em %s falhou, tentando /market_chart/range: %v", id, date.Format("02/01/2006"), err)
+		}
+		return fetchHistoryRange(ctx, id, date)
+	})
+	if err != nil {
+		var rl *rateLimitError
+		switch {
+		case errors.As(err, &rl):
+			return historicalPrice{}, fmt.Errorf("⏳ Limite de requisições do CoinGecko atingido, tente novamente em instantes")
+		case errors.Is(err, errUnsupportedCoin):
+			return historicalPrice{}, fmt.Errorf("❌ Sem histórico de preço para %s em %s", strings.ToUpper(coinDir.coin(id).Symbol), date.Format("02/01/2006"))
+		}
+		return historicalPrice{}, err
+	}
+
+	if past {
+		historyCacheMu.Lock()
+		historyCache[key] = price
+		historyCacheMu.Unlock()
+	}
+	return price, nil
+}
+
+// fetchHistorySnapshot consulta o preço de abertura do dia (00:00 UTC) em BRL e USD numa só chamada
+func fetchHistorySnapshot(ctx context.Context, id string, date time.Time) (historicalPrice, error) {
+	var data struct {
+		MarketData struct {
+			CurrentPrice map[string]float64 `json:"current_price"`
+		} `json:"market_data"`
+	}
+	if err := coingeckoGet(ctx, fmt.Sprintf(coinHistoryAPI, id, date.Format("02-01-2006")), &data); err != nil {
+		return historicalPrice{}, err
+	}
+	prices := data.MarketData.CurrentPrice
+	return historicalPrice{brl: prices["brl"], usd: prices["usd"]}, nil
+}
+
+// fetchHistoryRange pega o primeiro preço registrado no dia, uma chamada por moeda de cotação
+func fetchHistoryRange(ctx context.Context, id string, date time.Time) (historicalPrice, error) {
+	first := func(currency string) (float64, error) {
+		var data struct {
+			Prices [][2]float64 `json:"prices"`
+		}
+		url := fmt.Sprintf(chartRangeAPI, id, currency, date.Unix(), date.Add(24*time.Hour).Unix())
+		if err := coingeckoGet(ctx, url, &data); err != nil {
+			return 0, err
+		}
+		if len(data.Prices) == 0 {
+			return 0, errUnsupportedCoin
+		}
+		return data.Prices[0][1], nil
+	}
+
+	brl, err := first("brl")
+	if err != nil {
+		return historicalPrice{}, err
+	}
+	usd, err := first("usd")
+	if err != nil {
+		return historicalPrice{}, err
+	}
+	return historicalPrice{brl: brl, usd: usd}, nil
+}
+
+func percentChange(from, to float64) float64 {
+	if from == 0 {
+		return 0
+	}
+	return (to - from) / from * 100
+}
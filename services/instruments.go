@@ -0,0 +1,88 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+)
+
+// InstrumentKind classifica os ativos fora do mundo cripto
+type InstrumentKind string
+
+const (
+	KindStock     InstrumentKind = "stock"
+	KindIndex     InstrumentKind = "index"
+	KindFX        InstrumentKind = "fx"
+	KindCommodity InstrumentKind = "commodity"
+)
+
+// Instrument é um ativo do mercado tradicional (ação da B3, índice, par de moedas ou commodity).
+// Cada fonte usa sua própria nomenclatura; campo vazio = fonte não cobre o ativo.
+type Instrument struct {
+	Ticker string // como o usuário vê (PETR4, IBOV, USD/BRL, OURO)
+	Name   string // nome conhecido de antemão (as fontes podem trazer um melhor)
+	Kind   InstrumentKind
+	Yahoo  string // ex: PETR4.SA, ^BVSP, USDBRL=X, GC=F
+	Brapi  string // ex: PETR4, ^BVSP
+}
+
+// predefinedInstruments são índices, câmbio e commodities reconhecidos pelo nome popular.
+// Fica separado de PredefinedAliases; quando um nome também é de uma criptomoeda, vale a moeda.
+var predefinedInstruments = map[string]Instrument{
+	"ibov":     {Ticker: "IBOV", Name: "Ibovespa", Kind: KindIndex, Yahoo: "^BVSP", Brapi: "^BVSP"},
+	"ibovespa": {Ticker: "IBOV", Name: "Ibovespa", Kind: KindIndex, Yahoo: "^BVSP", Brapi: "^BVSP"},
+	"sp500":    {Ticker: "SPX", Name: "S&P 500", Kind: KindIndex, Yahoo: "^GSPC"},
+	"spx":      {Ticker: "SPX", Name: "S&P 500", Kind: KindIndex, Yahoo: "^GSPC"},
+	"nasdaq":   {Ticker: "IXIC", Name: "Nasdaq Composite", Kind: KindIndex, Yahoo: "^IXIC"},
+	"dowjones": {Ticker: "DJI", Name: "Dow Jones", Kind: KindIndex, Yahoo: "^DJI"},
+	"dolar":    {Ticker: "USD/BRL", Name: "Dólar comercial", Kind: KindFX, Yahoo: "USDBRL=X"},
+	"dólar":    {Ticker: "USD/BRL", Name: "Dólar comercial", Kind: KindFX, Yahoo: "USDBRL=X"},
+	"euro":     {Ticker: "EUR/BRL", Name: "Euro", Kind: KindFX, Yahoo: "EURBRL=X"},
+	"libra":    {Ticker: "GBP/BRL", Name: "Libra esterlina", Kind: KindFX, Yahoo: "GBPBRL=X"},
+	"ouro":     {Ticker: "OURO", Name: "Ouro (onça troy)", Kind: KindCommodity, Yahoo: "GC=F"},
+	"gold":     {Ticker: "OURO", Name: "Ouro (onça troy)", Kind: KindCommodity, Yahoo: "GC=F"},
+	"prata":    {Ticker: "PRATA", Name: "Prata (onça troy)", Kind: KindCommodity, Yahoo: "SI=F"},
+	"petroleo": {Ticker: "BRENT", Name: "Petróleo Brent (barril)", Kind: KindCommodity, Yahoo: "BZ=F"},
+	"petróleo": {Ticker: "BRENT", Name: "Petróleo Brent (barril)", Kind: KindCommodity, Yahoo: "BZ=F"},
+	"brent":    {Ticker: "BRENT", Name: "Petróleo Brent (barril)", Kind: KindCommodity, Yahoo: "BZ=F"},
+	"wti":      {Ticker: "WTI", Name: "Petróleo WTI (barril)", Kind: KindCommodity, Yahoo: "CL=F"},
+	"cafe":     {Ticker: "CAFE", Name: "Café arábica (libra-peso)", Kind: KindCommodity, Yahoo: "KC=F"},
+	"café":     {Ticker: "CAFE", Name: "Café arábica (libra-peso)", Kind: KindCommodity, Yahoo: "KC=F"},
+	"soja":     {Ticker: "SOJA", Name: "Soja (bushel)", Kind: KindCommodity, Yahoo: "ZS=F"},
+	"minerio":  {Ticker: "MINERIO", Name: "Minério de ferro (tonelada)", Kind: KindCommodity, Yahoo: "TIO=F"},
+	"minério":  {Ticker: "MINERIO", Name: "Minério de ferro (tonelada)", Kind: KindCommodity, Yahoo: "TIO=F"},
+}
+
+var (
+	// b3Ticker reconhece ações, units e FIIs da B3 (PETR4, VALE3, TAEE11, BOVA11)
+	b3Ticker = regexp.MustCompile(`^[a-z]{4}(3|4|5|6|11)$`)
+	// fxPair reconhece pares de moedas (USD/BRL, eurusd)
+	fxPair = regexp.MustCompile(`^([a-z]{3})/?([a-z]{3})$`)
+)
+
+// fxCurrencies são as moedas aceitas nos pares de câmbio digitados (ex: usd/jpy)
+var fxCurrencies = map[string]bool{
+	"usd": true, "brl": true, "eur": true, "gbp": true, "jpy": true, "chf": true,
+	"cad": true, "aud": true, "cny": true, "ars": true, "mxn": true, "clp": true,
+}
+
+// resolveInstrument reconhece tickers do mercado tradicional: nomes populares (ibov, dolar, ouro),
+// ações da B3 (petr4) e pares de câmbio (usd/brl)
+func resolveInstrument(input string) (Instrument, bool) {
+	s := strings.ToLower(strings.TrimSpace(input))
+
+	if inst, ok := predefinedInstruments[s]; ok {
+		return inst, true
+	}
+
+	if b3Ticker.MatchString(s) {
+		ticker := strings.ToUpper(s)
+		return Instrument{Ticker: ticker, Kind: KindStock, Yahoo: ticker + ".SA", Brapi: ticker}, true
+	}
+
+	if m := fxPair.FindStringSubmatch(s); m != nil && m[1] != m[2] && fxCurrencies[m[1]] && fxCurrencies[m[2]] {
+		base, quote := strings.ToUpper(m[1]), strings.ToUpper(m[2])
+		return Instrument{Ticker: base + "/" + quote, Kind: KindFX, Yahoo: base + quote + "=X"}, true
+	}
+
+	return Instrument{}, false
+}
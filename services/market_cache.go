@@ -0,0 +1,133 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+)
+
+// MarketQuote é o retrato de mercado de uma moeda, compartilhado por comandos e monitor
+type MarketQuote struct {
+	ID           string
+	Symbol       string
+	Name         string
+	Rank         int
+	PriceBRL     float64
+	PriceUSD     float64
+	Change1h     float64
+	Change24h    float64
+	NoChange24h  bool // a fonte não informa a variação de 24h (ex: Coinbase)
+	Change7d     float64
+	Change30d    float64
+	Change1y     float64
+	MarketCapBRL float64
+	VolumeBRL    float64
+	ATHUSD       float64
+	Provider     string // fonte que respondeu (ex: CoinGecko, Binance)
+	FetchedAt    time.Time
+}
+
+// Age informa há quanto tempo a cotação foi obtida
+func (q MarketQuote) Age() time.Duration {
+	return time.Since(q.FetchedAt)
+}
+
+var (
+	quoteCacheMu sync.RWMutex
+	quoteCache   = map[string]MarketQuote{} // id → última cotação
+	quoteFlight  flightGroup[MarketQuote]
+)
+
+// GetMarketQuote devolve a cotação de uma moeda (ID do CoinGecko), reaproveitando o cache por PRICE_CACHE_TTL.
+// Pedidos simultâneos da mesma moeda compartilham uma única requisição; se a API falhar
+// (ex: status 429), a última cotação conhecida é devolvida mesmo vencida.
+func GetMarketQuote(id string) (MarketQuote, error) {
+	quoteCacheMu.RLock()
+	cached, ok := quoteCache[id]
+	quoteCacheMu.RUnlock()
+
+	if ok && cached.Age() < config.AppConfig.PriceCacheTTL {
+		return cached, nil
+	}
+
+	quote, err := quoteFlight.Do(id, func() (MarketQuote, error) {
+		q, err := fetchMarketQuote(id)
+		if err != nil {
+			return q, err
+		}
+		quoteCacheMu.Lock()
+		quoteCache[id] = q
+		quoteCacheMu.Unlock()
+		return q, nil
+	})
+	if err != nil {
+		if ok {
+			log.Printf("⚠️ [%s] usando cotação de %s atrás: %v", id, cached.Age().Round(time.Second), err)
+			return cached, nil
+		}
+		return MarketQuote{}, err
+	}
+	return quote, nil
+}
+
+//
+// ========== 🛬 Agrupamento de requisições =========
+//
+
+// flightGroup garante uma única execução em andamento por chave; quem chega durante a
+// execução espera e recebe o mesmo resultado
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	wg  sync.WaitGroup
+	val T
+	err error
+}
+
+func (g *flightGroup[T]) Do(key string, fn func() (T, error)) (T, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall[T]{}
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err
+	}
+
+	c := &flightCall[T]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	c.val, c.err = fn()
+	return c.val, c.err
+}
+
+// formatAge resume a idade de uma cotação (ex: 45s, 3min); vazio quando acabou de ser obtida
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Second:
+		return ""
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dmin", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
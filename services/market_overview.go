@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+)
+
+const (
+	globalAPI    = "https://api.coingecko.com/api/v3/global"
+	fearGreedAPI = "https://api.alternative.me/fng/?limit=1"
+
+	overviewUniverse = 100 // altas e baixas do dia são calculadas entre as 100 maiores
+	overviewMovers   = 5
+	defaultTopCoins  = 10
+	maxTopCoins      = 25
+)
+
+// MarketOverview reúne os dados do template market_overview (!mercado)
+type MarketOverview struct {
+	MarketCapBRL    float64
+	MarketCapUSD    float64
+	MarketCapChange float64 // variação 24h do market cap total (USD)
+	VolumeBRL       float64
+	BTCDominance    float64
+	ETHDominance    float64
+	TopN            int
+	Top             string // tabela já alinhada para o bloco monoespaçado
+	Gainers         []MarketQuote
+	Losers          []MarketQuote
+	Sentiment       *Sentiment
+	Sources         string
+	FetchedAt       time.Time
+}
+
+// Sentiment é o índice Fear & Greed (0 = medo extremo, 100 = ganância extrema)
+type Sentiment struct {
+	Value          int
+	Classification string
+	UpdatedAt      time.Time
+}
+
+// marketSnapshot são os dados brutos do panorama; ficam em cache por PRICE_CACHE_TTL
+type marketSnapshot struct {
+	global    globalMarket
+	top       []MarketQuote
+	sentiment *Sentiment
+	fetchedAt time.Time
+}
+
+type globalMarket struct {
+	marketCap   map[string]float64
+	volume      map[string]float64
+	dominance   map[string]float64
+	capChange24 float64
+}
+
+var (
+	snapshotMu     sync.Mutex
+	snapshotCache  marketSnapshot
+	snapshotFlight flightGroup[marketSnapshot]
+)
+
+// GetMarketOverview monta o panorama do mercado (ex: "!mercado", "!mercado 20")
+func GetMarketOverview(args string) (string, error) {
+	topN := defaultTopCoins
+	if args = strings.TrimSpace(args); args != "" {
+		n, err := strconv.Atoi(args)
+		if err != nil || n < 1 || n > maxTopCoins {
+			return "", fmt.Errorf("⚠️ Uso: !mercado [quantidade de 1 a %d]", maxTopCoins)
+		}
+		topN = n
+	}
+
+	snap, err := getMarketSnapshot()
+	if err != nil {
+		return "", err
+	}
+
+	top := snap.top
+	if len(top) > topN {
+		top = top[:topN]
+	}
+
+	movers := append([]MarketQuote(nil), snap.top...)
+	sort.SliceStable(movers, func(i, j int) bool { return movers[i].Change24h > movers[j].Change24h })
+	var gainers, losers []MarketQuote
+	for i := 0; i < len(movers) && len(gainers) < overviewMovers && movers[i].Change24h > 0; i++ {
+		gainers = append(gainers, movers[i])
+	}
+	for i := len(movers) - 1; i >= 0 && len(losers) < overviewMovers && movers[i].Change24h < 0; i-- {
+		losers = append(losers, movers[i])
+	}
+
+	sources := "CoinGecko"
+	if snap.sentiment != nil {
+		sources += ", Alternative.me"
+	}
+
+	return RenderTemplate("market_overview", MarketOverview{
+		MarketCapBRL:    snap.global.marketCap["brl"],
+		MarketCapUSD:    snap.global.marketCap["usd"],
+		MarketCapChange: snap.global.capChange24,
+		VolumeBRL:       snap.global.volume["brl"],
+		BTCDominance:    snap.global.dominance["btc"],
+		ETHDominance:    snap.global.dominance["eth"],
+		TopN:            len(top),
+		Top:             buildQuoteTable(top),
+		Gainers:         gainers,
+		Losers:          losers,
+		Sentiment:       snap.sentiment,
+		Sources:         sources,
+		FetchedAt:       snap.fetchedAt,
+	})
+}
+
+// getMarketSnapshot devolve o panorama em cache ou busca /global, /coins/markets e o Fear & Greed
+func getMarketSnapshot() (marketSnapshot, error) {
+	snapshotMu.Lock()
+	cached := snapshotCache
+	snapshotMu.Unlock()
+	if !cached.fetchedAt.IsZero() && time.Since(cached.fetchedAt) < config.AppConfig.PriceCacheTTL {
+		return cached, nil
+	}
+
+	snap, err := snapshotFlight.Do("overview", func() (marketSnapshot, error) {
+		return fetchMarketSnapshot(context.Background())
+	})
+	if err != nil {
+		if !cached.fetchedAt.IsZero() {
+			log.Printf("⚠️ Panorama do mercado: usando dados de %s (%v)", cached.fetchedAt.Format("15:04:05"), err)
+			return cached, nil
+		}
+		return marketSnapshot{}, err
+	}
+
+	snapshotMu.Lock()
+	snapshotCache = snap
+	snapshotMu.Unlock()
+	return snap, nil
+}
+
+func fetchMarketSnapshot(ctx context.Context) (marketSnapshot, error) {
+	var data struct {
+		Data struct {
+			TotalMarketCap      map[string]float64 `json:"total_market_cap"`
+			TotalVolume         map[string]float64 `json:"total_volume"`
+			MarketCapPercentage map[string]float64 `json:"market_cap_percentage"`
+			MarketCapChange24h  float64            `json:"market_cap_change_percentage_24h_usd"`
+		} `json:"data"`
+	}
+	if err := coingeckoGet(ctx, globalAPI, &data); err != nil {
+		return marketSnapshot{}, err
+	}
+
+	top, err := fetchTopMarkets(ctx, overviewUniverse)
+	if err != nil {
+		return marketSnapshot{}, err
+	}
+	if len(top) == 0 {
+		return marketSnapshot{}, fmt.Errorf("❌ CoinGecko não retornou o ranking de moedas")
+	}
+
+	// O sentimento é complementar: sem ele, o panorama sai assim mesmo
+	sentiment, err := fetchFearGreed(ctx)
+	if err != nil {
+		log.Printf("⚠️ Índice Fear & Greed indisponível: %v", err)
+	}
+
+	return marketSnapshot{
+		global: globalMarket{
+			marketCap:   data.Data.TotalMarketCap,
+			volume:      data.Data.TotalVolume,
+			dominance:   data.Data.MarketCapPercentage,
+			capChange24: data.Data.MarketCapChange24h,
+		},
+		top:       top,
+		sentiment: sentiment,
+		fetchedAt: time.Now(),
+	}, nil
+}
+
+// fetchFearGreed consulta o índice Fear & Greed do alternative.me (valores chegam como texto)
+func fetchFearGreed(ctx context.Context) (*Sentiment, error) {
+	var data struct {
+		Data []struct {
+			Value          string `json:"value"`
+			Classification string `json:"value_classification"`
+			Timestamp      string `json:"timestamp"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, "Alternative.me", fearGreedAPI, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Data) == 0 {
+		return nil, fmt.Errorf("resposta sem dados")
+	}
+
+	d := data.Data[0]
+	value, err := strconv.Atoi(d.Value)
+	if err != nil {
+		return nil, fmt.Errorf("valor inválido %q", d.Value)
+	}
+	ts, _ := strconv.ParseInt(d.Timestamp, 10, 64)
+	return &Sentiment{Value: value, Classification: d.Classification, UpdatedAt: time.Unix(ts, 0)}, nil
+}
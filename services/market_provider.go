@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+)
+
+// MarketDataProvider é uma fonte de cotações (CoinGecko, corretoras...).
+// Campos que a fonte não oferece (rank, ATH, variações longas) ficam zerados.
+type MarketDataProvider interface {
+	Name() string
+	Quote(ctx context.Context, coin CoinData) (MarketQuote, error)
+}
+
+// marketProviders são as fontes conhecidas, indexadas pelo nome usado em MARKET_PROVIDERS
+var marketProviders = map[string]MarketDataProvider{
+	"coingecko": coingeckoProvider{},
+	"binance":   binanceProvider{},
+	"kraken":    krakenProvider{},
+	"coinbase":  coinbaseProvider{},
+}
+
+const defaultRateLimitPause = time.Minute
+
+var (
+	errUnsupportedCoin = errors.New("moeda não listada nesta fonte")
+
+	providerPauseMu sync.Mutex
+	providerPause   = map[string]time.Time{} // fonte → pausada até (após HTTP 429)
+)
+
+// rateLimitError indica que a fonte pediu para reduzir o ritmo (HTTP 429)
+type rateLimitError struct {
+	provider   string
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("%s limitou as requisições (HTTP 429)", e.provider)
+}
+
+// activeProviders devolve as fontes na ordem de prioridade de MARKET_PROVIDERS
+func activeProviders() []MarketDataProvider {
+	var list []MarketDataProvider
+	for _, name := range config.AppConfig.MarketProviders {
+		if p, ok := marketProviders[strings.ToLower(name)]; ok {
+			list = append(list, p)
+		}
+	}
+	if len(list) == 0 {
+		list = append(list, marketProviders["coingecko"])
+	}
+	return list
+}
+
+// fetchMarketQuote consulta as fontes em ordem de prioridade, pulando as que estão pausadas por
+// limite de requisições e passando para a próxima em caso de erro
+func fetchMarketQuote(id string) (MarketQuote, error) {
+	ctx := context.Background()
+	coin := coinDir.coin(id)
+
+	var failures []string
+	for _, p := range activeProviders() {
+		if until, paused := providerPausedUntil(p.Name()); paused {
+			failures = append(failures, fmt.Sprintf("%s: pausada até %s", p.Name(), until.Format("15:04:05")))
+			continue
+		}
+
+		q, err := p.Quote(ctx, coin)
+		if err == nil {
+			q.ID = id
+			q.Provider = p.Name()
+			q.FetchedAt = time.Now()
+			return q, nil
+		}
+
+		var rl *rateLimitError
+		if errors.As(err, &rl) {
+			pauseProvider(p.Name(), rl.retryAfter)
+		}
+		if !errors.Is(err, errUnsupportedCoin) {
+			log.Printf("⚠️ [%s] %s falhou, tentando a próxima fonte: %v", id, p.Name(), err)
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", p.Name(), err))
+	}
+
+	return MarketQuote{}, fmt.Errorf("❌ Nenhuma fonte de cotação respondeu para '%s' (%s)", id, strings.Join(failures, "; "))
+}
+
+func providerPausedUntil(name string) (time.Time, bool) {
+	providerPauseMu.Lock()
+	defer providerPauseMu.Unlock()
+	until := providerPause[name]
+	return until, time.Now().Before(until)
+}
+
+func pauseProvider(name string, d time.Duration) {
+	if d <= 0 {
+		d = defaultRateLimitPause
+	}
+	providerPauseMu.Lock()
+	providerPause[name] = time.Now().Add(d)
+	providerPauseMu.Unlock()
+	log.Printf("⏸️ Fonte %s pausada por %s (limite de requisições)", name, d)
+}
+
+//
+// ========== 🧰 Utilitários =========
+//
+
+// getJSON faz um GET e decodifica a resposta; 429 vira rateLimitError e 400/404 viram errUnsupportedCoin
+func getJSON(ctx context.Context, provider, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	// Algumas fontes (ex: Yahoo) recusam requisições sem User-Agent
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; whatsapp-bot)")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("🌐 Erro HTTP ao acessar %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		retry, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return &rateLimitError{provider: provider, retryAfter: time.Duration(retry) * time.Second}
+	case http.StatusBadRequest, http.StatusNotFound:
+		return errUnsupportedCoin
+	default:
+		return fmt.Errorf("❌ %s retornou status %d", provider, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("📦 Erro ao decodificar resposta de %s: %w", provider, err)
+	}
+	return nil
+}
+
+// parseFloat converte os números enviados como texto pelas corretoras
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// usdToBRL devolve a cotação do dólar em reais, usada pelas corretoras que só cotam em USD/USDT
+func usdToBRL(ctx context.Context) (float64, error) {
+	rates, err := GetFiatRates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return rates.PerUSD["brl"], nil
+}
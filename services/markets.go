@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+)
+
+const (
+	coinMarketsAPI = "https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&price_change_percentage=24h&per_page=250&ids=%s"
+	topMarketsAPI  = "https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&order=market_cap_desc&price_change_percentage=24h&per_page=%d&page=1"
+
+	// coinMarketsMaxIDs é o per_page de coinMarketsAPI: acima disso, a resposta vem cortada
+	coinMarketsMaxIDs = 250
+)
+
+var (
+	marketsCacheMu sync.RWMutex
+	marketsCache   = map[string]MarketQuote{} // id → linha resumida (preço, 24h, rank, ATH)
+	marketsFlight  flightGroup[[]MarketQuote]
+)
+
+// GetMarketsBatch devolve cotações resumidas de várias moedas com uma chamada a /coins/markets por
+// bloco de até 250 moedas.
+// Moedas ainda válidas no cache não são pedidas de novo; se o CoinGecko falhar, cada moeda
+// pendente é consultada pelas fontes de reserva (GetMarketQuote).
+func GetMarketsBatch(ids []string) ([]MarketQuote, error) {
+	ttl := config.AppConfig.PriceCacheTTL
+	found := map[string]MarketQuote{}
+	var missing []string
+
+	marketsCacheMu.RLock()
+	for _, id := range ids {
+		if q, ok := marketsCache[id]; ok && q.Age() < ttl {
+			found[id] = q
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	marketsCacheMu.RUnlock()
+
+	if len(missing) > 0 {
+		sorted := append([]string(nil), missing...)
+		sort.Strings(sorted)
+		key := strings.Join(sorted, ",")
+
+		fetched, err := marketsFlight.Do(key, func() ([]MarketQuote, error) {
+			return fetchCoinMarkets(context.Background(), sorted)
+		})
+		if err != nil {
+			log.Printf("⚠️ /coins/markets falhou, consultando moeda a moeda: %v", err)
+		}
+
+		for _, q := range fetched {
+			found[q.ID] = q
+		}
+		for _, id := range missing {
+			if _, ok := found[id]; ok {
+				continue
+			}
+			if q, err := GetMarketQuote(id); err == nil {
+				found[id] = q
+			}
+		}
+	}
+
+	quotes := make([]MarketQuote, 0, len(ids))
+	for _, id := range ids {
+		if q, ok := found[id]; ok {
+			quotes = append(quotes, q)
+		}
+	}
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("❌ Nenhuma cotação disponível no momento")
+	}
+	return quotes, nil
+}
+
+// fetchCoinMarkets consulta /coins/markets das moedas informadas, em blocos de até coinMarketsMaxIDs.
+// Se um bloco falhar, devolve o que já veio junto com o erro (o restante cai nas fontes de reserva).
+func fetchCoinMarkets(ctx context.Context, ids []string) ([]MarketQuote, error) {
+	var quotes []MarketQuote
+	for start := 0; start < len(ids); start += coinMarketsMaxIDs {
+		end := min(start+coinMarketsMaxIDs, len(ids))
+		chunk, err := fetchMarkets(ctx, fmt.Sprintf(coinMarketsAPI, url.QueryEscape(strings.Join(ids[start:end], ","))))
+		if err != nil {
+			return quotes, err
+		}
+		quotes = append(quotes, chunk...)
+	}
+	return quotes, nil
+}
+
+// fetchTopMarkets consulta as n primeiras moedas por market cap (máx. 250)
+func fetchTopMarkets(ctx context.Context, n int) ([]MarketQuote, error) {
+	return fetchMarkets(ctx, fmt.Sprintf(topMarketsAPI, n))
+}
+
+// fetchMarkets consulta /coins/markets em USD, converte para BRL pelo câmbio em cache e atualiza o cache
+func fetchMarkets(ctx context.Context, endpoint string) ([]MarketQuote, error) {
+	var rows []struct {
+		ID            string  `json:"id"`
+		Symbol        string  `json:"symbol"`
+		Name          string  `json:"name"`
+		CurrentPrice  float64 `json:"current_price"`
+		MarketCap     float64 `json:"market_cap"`
+		MarketCapRank int     `json:"market_cap_rank"`
+		TotalVolume   float64 `json:"total_volume"`
+		Change24h     float64 `json:"price_change_percentage_24h"`
+		ATH           float64 `json:"ath"`
+	}
+	if err := coingeckoGet(ctx, endpoint, &rows); err != nil {
+		return nil, err
+	}
+
+	rate, err := usdToBRL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	quotes := make([]MarketQuote, 0, len(rows))
+	for _, r := range rows {
+		quotes = append(quotes, MarketQuote{
+			ID:           r.ID,
+			Symbol:       r.Symbol,
+			Name:         r.Name,
+			Rank:         r.MarketCapRank,
+			PriceUSD:     r.CurrentPrice,
+			PriceBRL:     r.CurrentPrice * rate,
+			Change24h:    r.Change24h,
+			MarketCapBRL: r.MarketCap * rate,
+			VolumeBRL:    r.TotalVolume * rate,
+			ATHUSD:       r.ATH,
+			Provider:     "CoinGecko",
+			FetchedAt:    now,
+		})
+	}
+
+	marketsCacheMu.Lock()
+	for _, q := range quotes {
+		marketsCache[q.ID] = q
+	}
+	marketsCacheMu.Unlock()
+
+	return quotes, nil
+}
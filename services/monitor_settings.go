@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/store"
+)
+
+const (
+	monitorCoinsKey    = "monitor_coins"
+	monitorIntervalKey = "monitor_interval"
+
+	minMonitorInterval = time.Minute
+	maxMonitorInterval = 24 * time.Hour
+	maxMonitoredCoins  = 250 // limite de moedas por chamada a /coins/markets
+)
+
+var (
+	monitorMu       sync.RWMutex
+	monitorCoins    []string      // aliases como digitados (btc, eth...); resolvidos a cada verificação
+	monitorInterval time.Duration // 0 = ainda não carregado (usa a configuração)
+
+	// monitorReset avisa o loop do monitor que o intervalo mudou
+	monitorReset = make(chan struct{}, 1)
+)
+
+// MonitorSettings resume a configuração atual do monitor (template monitor_status)
+type MonitorSettings struct {
+	Coins    []string
+	Interval string            // ex: 5min, 1h
+	Records  []store.ATHRecord // recorde avisado e último preço de cada moeda monitorada
+}
+
+// MonitoredCoins devolve as moedas verificadas pelo monitor de ATH
+func MonitoredCoins() []string {
+	monitorMu.RLock()
+	defer monitorMu.RUnlock()
+	if monitorCoins == nil {
+		return config.AppConfig.MonitoredCoins
+	}
+	return append([]string(nil), monitorCoins...)
+}
+
+// MonitorInterval devolve o intervalo entre verificações do monitor
+func MonitorInterval() time.Duration {
+	monitorMu.RLock()
+	defer monitorMu.RUnlock()
+	if monitorInterval <= 0 {
+		return max(config.AppConfig.MonitorInterval, minMonitorInterval)
+	}
+	return monitorInterval
+}
+
+// MonitorStatus mostra as moedas, o intervalo e os recordes do monitor (!monitor)
+func MonitorStatus(ctx context.Context) (string, error) {
+	settings := MonitorSettings{Coins: MonitoredCoins(), Interval: formatWindow(MonitorInterval())}
+
+	if store.DB != nil {
+		records, err := store.LoadATHRecords(ctx)
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+		for _, alias := range settings.Coins {
+			id, err := resolveCoin(alias)
+			if err != nil {
+				continue
+			}
+			if r, ok := records[strings.ToUpper(coinDir.coin(id).Symbol)]; ok && r.LastSeenAt != nil {
+				settings.Records = append(settings.Records, r)
+			}
+		}
+	}
+
+	return RenderTemplate("monitor_status", settings)
+}
+
+// SetMonitoredCoins troca a lista de moedas monitoradas; cada alias precisa ser reconhecido
+func SetMonitoredCoins(ctx context.Context, coins []string) error {
+	if len(coins) == 0 {
+		return fmt.Errorf("⚠️ Informe ao menos uma moeda (ex: !monitor moedas btc eth sol)")
+	}
+	if len(coins) > maxMonitoredCoins {
+		return fmt.Errorf("⚠️ O monitor aceita até %d moedas", maxMonitoredCoins)
+	}
+
+	var list []string
+	for _, alias := range coins {
+		alias = strings.ToLower(alias)
+		if _, err := resolveCoin(alias); err != nil {
+			return err
+		}
+		if !containsString(list, alias) {
+			list = append(list, alias)
+		}
+	}
+
+	monitorMu.Lock()
+	monitorCoins = list
+	monitorMu.Unlock()
+
+	log.Printf("🔍 Moedas monitoradas: %s", strings.Join(list, ", "))
+	return saveMonitorSetting(ctx, monitorCoinsKey, strings.Join(list, " "))
+}
+
+// AddMonitoredCoins inclui moedas na lista atual
+func AddMonitoredCoins(ctx context.Context, coins []string) error {
+	return SetMonitoredCoins(ctx, append(MonitoredCoins(), coins...))
+}
+
+// RemoveMonitoredCoins tira moedas da lista atual (comparando pelo ID, então "btc" remove "bitcoin")
+func RemoveMonitoredCoins(ctx context.Context, coins []string) error {
+	remove := map[string]bool{}
+	for _, alias := range coins {
+		id, err := resolveCoin(alias)
+		if err != nil {
+			return err
+		}
+		remove[id] = true
+	}
+
+	var kept []string
+	for _, alias := range MonitoredCoins() {
+		if id, err := resolveCoin(alias); err == nil && remove[id] {
+			continue
+		}
+		kept = append(kept, alias)
+	}
+	return SetMonitoredCoins(ctx, kept)
+}
+
+// SetMonitorInterval altera o intervalo do monitor (ex: "10m", "1h"); vale já para a próxima verificação
+func SetMonitorInterval(ctx context.Context, input string) error {
+	d, err := time.ParseDuration(strings.TrimSpace(input))
+	if err != nil {
+		var ok bool
+		if d, ok = parseAlertDuration(strings.ToLower(strings.TrimSpace(input))); !ok {
+			return fmt.Errorf("⚠️ Intervalo inválido '%s' (ex: 5m, 30min, 1h)", input)
+		}
+	}
+	if d < minMonitorInterval || d > maxMonitorInterval {
+		return fmt.Errorf("⚠️ O intervalo precisa ficar entre %s e %s", formatWindow(minMonitorInterval), formatWindow(maxMonitorInterval))
+	}
+
+	monitorMu.Lock()
+	monitorInterval = d
+	monitorMu.Unlock()
+
+	select {
+	case monitorReset <- struct{}{}:
+	default:
+	}
+
+	log.Printf("🔍 Intervalo do monitor: %s", d)
+	return saveMonitorSetting(ctx, monitorIntervalKey, d.String())
+}
+
+// loadMonitorSettings aplica os ajustes feitos com !monitor antes do último reinício
+func loadMonitorSettings(ctx context.Context) {
+	if store.DB == nil {
+		return
+	}
+
+	if value, ok, err := store.GetChatSetting(ctx, store.GlobalChat, monitorCoinsKey); err != nil {
+		log.Printf("⚠️ %v", err)
+	} else if ok && value != "" {
+		monitorMu.Lock()
+		monitorCoins = strings.Fields(value)
+		monitorMu.Unlock()
+	}
+
+	if value, ok, err := store.GetChatSetting(ctx, store.GlobalChat, monitorIntervalKey); err != nil {
+		log.Printf("⚠️ %v", err)
+	} else if d, perr := time.ParseDuration(value); ok && perr == nil && d >= minMonitorInterval {
+		monitorMu.Lock()
+		monitorInterval = d
+		monitorMu.Unlock()
+	}
+
+	log.Printf("🔍 Monitor: %d moeda(s) a cada %s", len(MonitoredCoins()), MonitorInterval())
+}
+
+// saveMonitorSetting persiste o ajuste; sem banco, a mudança vale só até o próximo reinício
+func saveMonitorSetting(ctx context.Context, key, value string) error {
+	if store.DB == nil {
+		log.Printf("⚠️ Banco desconectado: ajuste '%s' do monitor não será mantido após reiniciar", key)
+		return nil
+	}
+	return store.SetChatSetting(ctx, store.GlobalChat, key, value)
+}
@@ -60,6 +60,17 @@ func removeDuplicates(trending, news []panicPost) []panicPost {
 	return filtered
 }
 
+// renderNewsSection aplica o template da seção com no máximo maxItemsPerSection notícias
+func renderNewsSection(name string, posts []panicPost) (string, error) {
+	if len(posts) > maxItemsPerSection {
+		posts = posts[:maxItemsPerSection]
+	}
+	return RenderTemplate(name, struct {
+		Posts  []panicPost
+		Source string
+	}{posts, cryptoPanicBaseURL})
+}
+
 func GetCryptoNews() (string, string, error) {
 	hotURL := fmt.Sprintf("https://cryptopanic.com/api/v1/posts/?auth_token=%s&filter=hot&public=true", cryptoPanicToken)
 	allURL := fmt.Sprintf("https://cryptopanic.com/api/v1/posts/?auth_token=%s&kind=news&public=true", cryptoPanicToken)
@@ -76,34 +87,14 @@ func GetCryptoNews() (string, string, error) {
 
 	newsPosts = removeDuplicates(hotPosts, newsPosts)
 
-	var hotBuilder strings.Builder
-	hotBuilder.WriteString("📰 *Resumo Cripto do Dia*\n")
-	hotBuilder.WriteString("──────────────────────\n\n🔥 *Mais Quentes do Momento*\n\n")
-	if len(hotPosts) == 0 {
-		hotBuilder.WriteString("⚠️ Nenhuma notícia quente no momento.\n")
-	} else {
-		for i, post := range hotPosts {
-			if i >= maxItemsPerSection {
-				break
-			}
-			hotBuilder.WriteString(fmt.Sprintf("*%d.* %s\n\n", i+1, strings.TrimSpace(post.Title)))
-		}
-		hotBuilder.WriteString(fmt.Sprintf("🔗 (Fonte: %s)\n", cryptoPanicBaseURL))
+	hotMsg, err := renderNewsSection("news_hot", hotPosts)
+	if err != nil {
+		return "", "", err
 	}
 
-	var newsBuilder strings.Builder
-	newsBuilder.WriteString("🗞️ *Últimas Notícias*\n")
-	newsBuilder.WriteString("──────────────────────\n\n")
-	if len(newsPosts) == 0 {
-		newsBuilder.WriteString("⚠️ Nenhuma notícia recente disponível.\n")
-	} else {
-		for i, post := range newsPosts {
-			if i >= maxItemsPerSection {
-				break
-			}
-			newsBuilder.WriteString(fmt.Sprintf("*%d.* %s\n\n", i+1, strings.TrimSpace(post.Title)))
-		}
-		newsBuilder.WriteString(fmt.Sprintf("🔗 (Fonte: %s)\n", cryptoPanicBaseURL))
+	newsMsg, err := renderNewsSection("news_latest", newsPosts)
+	if err != nil {
+		return "", "", err
 	}
 
 	translate := func(txt string) string {
@@ -117,8 +108,8 @@ func GetCryptoNews() (string, string, error) {
 		return result
 	}
 
-	translatedHot := translate(hotBuilder.String())
-	translatedNews := translate(newsBuilder.String())
+	translatedHot := translate(hotMsg)
+	translatedNews := translate(newsMsg)
 
 	log.Printf("🔍 Total hot: %d | Total news (filtradas): %d", len(hotPosts), len(newsPosts))
 
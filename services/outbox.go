@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	p "google.golang.org/protobuf/proto"
+)
+
+const (
+	outboxBatchSize  = 50
+	outboxBaseDelay  = 5 * time.Second
+	outboxMaxDelay   = 10 * time.Minute
+	outboxPreviewLen = 80
+)
+
+var (
+	outboxRunning atomic.Bool
+	outboxWake    = make(chan struct{}, 1)
+
+	// outboxDB grava o andamento das mensagens da fila (trocado por uma versão em memória nos testes)
+	outboxDB outboxStore = dbOutbox{}
+)
+
+// outboxStore são as gravações de status feitas pelo worker da fila de saída
+type outboxStore interface {
+	SetMessageID(ctx context.Context, id int64, messageID string) error
+	MarkSent(ctx context.Context, id int64, messageID string) error
+	MarkRetry(ctx context.Context, id int64, next time.Time, cause string, countAttempt bool) error
+	MarkFailed(ctx context.Context, id int64, cause string) error
+}
+
+type dbOutbox struct{}
+
+func (dbOutbox) SetMessageID(ctx context.Context, id int64, messageID string) error {
+	return store.SetOutboxMessageID(ctx, id, messageID)
+}
+
+func (dbOutbox) MarkSent(ctx context.Context, id int64, messageID string) error {
+	return store.MarkOutboxSent(ctx, id, messageID)
+}
+
+func (dbOutbox) MarkRetry(ctx context.Context, id int64, next time.Time, cause string, countAttempt bool) error {
+	return store.MarkOutboxRetry(ctx, id, next, cause, countAttempt)
+}
+
+func (dbOutbox) MarkFailed(ctx context.Context, id int64, cause string) error {
+	return store.MarkOutboxFailed(ctx, id, cause)
+}
+
+// Queued embrulha o transporte para que todo envio passe pela fila de saída quando ela estiver ativa.
+// Sem fila (banco indisponível), as mensagens seguem direto para o transporte.
+func Queued(d transport.Deliverer) transport.Messenger {
+	return queuedMessenger{d}
+}
+
+type queuedMessenger struct {
+	transport.Deliverer
+}
+
+// Send grava a mensagem na fila de saída (com retentativas) ou, sem fila ativa, envia na hora
+func (q queuedMessenger) Send(ctx context.Context, to types.JID, msg *proto.Message, preview string) error {
+	if outboxRunning.Load() {
+		id, err := EnqueueMessage(ctx, to, msg, preview)
+		if err == nil {
+			log.Printf("📥 Mensagem #%d enfileirada para %s", id, to.String())
+			return nil
+		}
+		log.Printf("⚠️ Falha ao enfileirar, enviando diretamente: %v", err)
+	}
+
+	if err := q.Deliverer.Send(ctx, to, msg, preview); err != nil {
+		return err
+	}
+	log.Printf("📤 Mensagem enviada para %s", to.String())
+	return nil
+}
+
+// StartOutbox inicia o worker da fila de saída persistida no PostgreSQL.
+// Sem banco disponível, os envios via Queued continuam diretos.
+func StartOutbox(ctx context.Context, d transport.Deliverer) {
+	if store.DB == nil {
+		log.Println("⚠️ Fila de saída desativada: banco de dados indisponível.")
+		return
+	}
+
+	limiter := newSendLimiter(config.AppConfig.OutboxGlobalRate, config.AppConfig.OutboxChatRate)
+	outboxRunning.Store(true)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("🔥 Panic recuperado no worker da fila de saída: %v", r)
+			}
+			outboxRunning.Store(false)
+		}()
+
+		ticker := time.NewTicker(config.AppConfig.OutboxPoll)
+		defer ticker.Stop()
+
+		log.Println("📮 Fila de saída ativa — aguardando mensagens...")
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-outboxWake:
+			}
+
+			// Segura as mensagens enquanto o cliente estiver desconectado
+			if !d.Connected() {
+				continue
+			}
+
+			processOutbox(ctx, d, limiter)
+		}
+	}()
+}
+
+// EnqueueMessage grava a mensagem na fila de saída e retorna o ID do registro
+func EnqueueMessage(ctx context.Context, to types.JID, msg *proto.Message, preview string) (int64, error) {
+	payload, err := p.Marshal(msg)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Erro ao serializar mensagem: %w", err)
+	}
+
+	id, err := store.EnqueueOutbox(ctx, to.String(), payload, truncate(preview, outboxPreviewLen))
+	if err != nil {
+		return 0, err
+	}
+
+	select {
+	case outboxWake <- struct{}{}:
+	default:
+	}
+	return id, nil
+}
+
+// OutboxStatus retorna o estado atual de uma mensagem enfileirada
+func OutboxStatus(ctx context.Context, id int64) (*store.OutboxMessage, error) {
+	if store.DB == nil {
+		return nil, fmt.Errorf("⚠️ Fila de saída indisponível")
+	}
+	return store.GetOutbox(ctx, id)
+}
+
+// processOutbox envia as mensagens vencidas respeitando os limites global e por destinatário
+func processOutbox(ctx context.Context, d transport.Deliverer, limiter *sendLimiter) {
+	due, err := store.DueOutbox(ctx, outboxBatchSize)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return
+	}
+
+	// Destinatários adiados neste ciclo não podem furar a ordem das próprias mensagens
+	held := map[string]bool{}
+
+	for _, item := range due {
+		if held[item.Recipient] {
+			continue
+		}
+		if !limiter.chatReady(item.Recipient) {
+			held[item.Recipient] = true
+			continue
+		}
+		if err := limiter.waitGlobal(ctx); err != nil {
+			return
+		}
+
+		if !deliverOutbox(ctx, d, item) {
+			held[item.Recipient] = true
+		}
+		limiter.record(item.Recipient)
+	}
+}
+
+// deliverOutbox tenta enviar uma mensagem e atualiza seu status; retorna false em caso de falha
+func deliverOutbox(ctx context.Context, d transport.Deliverer, item store.OutboxMessage) bool {
+	jid, err := types.ParseJID(item.Recipient)
+	if err != nil {
+		markFailed(ctx, item, fmt.Sprintf("JID inválido: %v", err))
+		return false
+	}
+
+	var msg proto.Message
+	if err := p.Unmarshal(item.Payload, &msg); err != nil {
+		markFailed(ctx, item, fmt.Sprintf("payload inválido: %v", err))
+		return false
+	}
+
+	// O ID é salvo antes do primeiro envio: se o WhatsApp recebeu a mensagem mas o erro (ou um
+	// reinício) veio antes de MarkSent, a retentativa usa o mesmo ID e não duplica a mensagem
+	messageID := item.MessageID
+	if messageID == "" {
+		messageID = d.NewMessageID()
+		if err := outboxDB.SetMessageID(ctx, item.ID, messageID); err != nil {
+			log.Printf("⚠️ Mensagem #%d adiada: ID não foi salvo: %v", item.ID, err)
+			return false
+		}
+	}
+
+	err = d.Deliver(ctx, jid, &msg, messageID)
+	if err == nil {
+		if err := outboxDB.MarkSent(ctx, item.ID, messageID); err != nil {
+			log.Printf("⚠️ Mensagem #%d enviada, mas status não foi salvo: %v", item.ID, err)
+		}
+		log.Printf("📤 Mensagem #%d enviada para %s", item.ID, item.Recipient)
+		return true
+	}
+
+	switch {
+	case errors.Is(err, whatsmeow.ErrNotConnected), errors.Is(err, whatsmeow.ErrNotLoggedIn):
+		// Conexão caiu no meio do ciclo: reagenda sem consumir tentativa
+		_ = outboxDB.MarkRetry(ctx, item.ID, time.Now().Add(outboxBaseDelay), err.Error(), false)
+		log.Printf("⏸️ Mensagem #%d aguardando reconexão", item.ID)
+
+	case isPermanentSendError(err) || item.Attempts+1 >= config.AppConfig.OutboxMaxAttempts:
+		markFailed(ctx, item, err.Error())
+
+	default:
+		next := time.Now().Add(retryBackoff(item.Attempts + 1))
+		_ = outboxDB.MarkRetry(ctx, item.ID, next, err.Error(), true)
+		log.Printf("🔁 Mensagem #%d falhou (tentativa %d): %v — nova tentativa às %s",
+			item.ID, item.Attempts+1, err, next.Format("15:04:05"))
+	}
+	return false
+}
+
+func markFailed(ctx context.Context, item store.OutboxMessage, cause string) {
+	if err := outboxDB.MarkFailed(ctx, item.ID, cause); err != nil {
+		log.Printf("⚠️ Erro ao marcar mensagem #%d como falha: %v", item.ID, err)
+	}
+	log.Printf("❌ Mensagem #%d para %s descartada: %s", item.ID, item.Recipient, cause)
+}
+
+// isPermanentSendError identifica erros que não melhoram com novas tentativas
+func isPermanentSendError(err error) bool {
+	return errors.Is(err, whatsmeow.ErrRecipientADJID) ||
+		errors.Is(err, whatsmeow.ErrUnknownServer) ||
+		errors.Is(err, whatsmeow.ErrBroadcastListUnsupported) ||
+		errors.Is(err, whatsmeow.ErrNotInGroup) ||
+		errors.Is(err, whatsmeow.ErrGroupNotFound)
+}
+
+// retryBackoff calcula o atraso exponencial com jitter para a tentativa informada
+func retryBackoff(attempt int) time.Duration {
+	delay := outboxBaseDelay << (attempt - 1)
+	if delay <= 0 || delay > outboxMaxDelay {
+		delay = outboxMaxDelay
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay / 4)))
+	return delay + jitter
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max]) + "…"
+}
+
+//
+// ========== 🚦 Limite de envio =========
+//
+
+// sendLimiter garante um intervalo mínimo entre envios, global e por destinatário
+type sendLimiter struct {
+	mu         sync.Mutex
+	global     time.Duration
+	perChat    time.Duration
+	lastGlobal time.Time
+	lastChat   map[string]time.Time
+}
+
+func newSendLimiter(globalPerMinute, chatPerMinute int) *sendLimiter {
+	return &sendLimiter{
+		global:   perMinute(globalPerMinute),
+		perChat:  perMinute(chatPerMinute),
+		lastChat: map[string]time.Time{},
+	}
+}
+
+func perMinute(rate int) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	return time.Minute / time.Duration(rate)
+}
+
+// chatReady informa se o destinatário já pode receber outra mensagem
+func (l *sendLimiter) chatReady(chat string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return time.Since(l.lastChat[chat]) >= l.perChat
+}
+
+// waitGlobal bloqueia até o próximo envio ser permitido pelo limite global
+func (l *sendLimiter) waitGlobal(ctx context.Context) error {
+	l.mu.Lock()
+	wait := l.global - time.Since(l.lastGlobal)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (l *sendLimiter) record(chat string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.lastGlobal = now
+	l.lastChat[chat] = now
+}
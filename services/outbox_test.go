@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
+	"go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	p "google.golang.org/protobuf/proto"
+)
+
+// memoryOutbox guarda o andamento da fila em memória, como o banco faria
+type memoryOutbox struct {
+	rows map[int64]*store.OutboxMessage
+}
+
+func (m *memoryOutbox) SetMessageID(_ context.Context, id int64, messageID string) error {
+	if m.rows[id].MessageID == "" {
+		m.rows[id].MessageID = messageID
+	}
+	return nil
+}
+
+func (m *memoryOutbox) MarkSent(_ context.Context, id int64, messageID string) error {
+	m.rows[id].Status, m.rows[id].MessageID = store.OutboxSent, messageID
+	m.rows[id].Attempts++
+	return nil
+}
+
+func (m *memoryOutbox) MarkRetry(_ context.Context, id int64, _ time.Time, cause string, countAttempt bool) error {
+	m.rows[id].LastError = cause
+	if countAttempt {
+		m.rows[id].Attempts++
+	}
+	return nil
+}
+
+func (m *memoryOutbox) MarkFailed(_ context.Context, id int64, cause string) error {
+	m.rows[id].Status, m.rows[id].LastError = store.OutboxFailed, cause
+	return nil
+}
+
+// flakyDeliverer registra o ID de cada tentativa e falha a primeira, como um envio que chegou
+// ao WhatsApp mas estourou o tempo do lado do bot
+type flakyDeliverer struct {
+	*transport.Memory
+	attempts []string
+}
+
+func (f *flakyDeliverer) Deliver(ctx context.Context, to types.JID, msg *proto.Message, messageID string) error {
+	f.attempts = append(f.attempts, messageID)
+	if len(f.attempts) == 1 {
+		return errors.New("context deadline exceeded")
+	}
+	return f.Memory.Deliver(ctx, to, msg, messageID)
+}
+
+func TestDeliverOutboxRetryKeepsMessageID(t *testing.T) {
+	saved, savedCfg := outboxDB, config.AppConfig
+	t.Cleanup(func() { outboxDB, config.AppConfig = saved, savedCfg })
+	config.AppConfig.OutboxMaxAttempts = 5
+
+	msg, _ := transport.TextMessage("oi", nil)
+	payload, err := p.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := types.NewJID("5511999999999", types.DefaultUserServer)
+	db := &memoryOutbox{rows: map[int64]*store.OutboxMessage{
+		1: {ID: 1, Recipient: to.String(), Payload: payload, Status: store.OutboxPending},
+	}}
+	outboxDB = db
+
+	d := &flakyDeliverer{Memory: transport.NewMemory()}
+	ctx := context.Background()
+
+	// Cada ciclo relê a mensagem da fila, como DueOutbox faria
+	if deliverOutbox(ctx, d, *db.rows[1]) {
+		t.Fatal("primeira tentativa deveria falhar")
+	}
+	if db.rows[1].Status != store.OutboxPending {
+		t.Fatalf("status após a falha = %s, quero %s", db.rows[1].Status, store.OutboxPending)
+	}
+	if !deliverOutbox(ctx, d, *db.rows[1]) {
+		t.Fatal("retentativa deveria enviar")
+	}
+
+	if len(d.attempts) != 2 || d.attempts[0] == "" || d.attempts[0] != d.attempts[1] {
+		t.Fatalf("IDs das tentativas = %v, quero o mesmo ID nas duas", d.attempts)
+	}
+	sent := d.Sent()
+	if len(sent) != 1 || sent[0].MessageID != d.attempts[0] {
+		t.Fatalf("enviadas = %+v, quero uma mensagem com ID %s", sent, d.attempts[0])
+	}
+	if db.rows[1].Status != store.OutboxSent || db.rows[1].MessageID != d.attempts[0] || db.rows[1].Attempts != 2 {
+		t.Errorf("registro final = %+v", db.rows[1])
+	}
+}
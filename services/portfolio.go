@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/utils"
+)
+
+// portfolioUsage é a ajuda exibida quando o !carteira não é entendido
+const portfolioUsage = "⚠️ Uso: !carteira add <moeda> <quantidade> [@ <preço>] [brl|usd]\n" +
+	"ou: !carteira vender <moeda> <quantidade> [@ <preço>] [brl|usd]\n" +
+	"Ex: !carteira add btc 0.15 @ 320000 | !carteira vender eth 1 @ 2500 usd"
+
+// dustQuantity é o saldo abaixo do qual a posição é considerada zerada (arredondamentos de float)
+const dustQuantity = 1e-12
+
+// PositionView é uma moeda da carteira, com custo médio, valor atual e lucro/prejuízo não realizado
+type PositionView struct {
+	Symbol, Name       string
+	Quantity           float64
+	AvgBRL, AvgUSD     float64 // custo médio por unidade
+	PriceBRL, PriceUSD float64 // 0 quando a cotação está indisponível
+	CostBRL, CostUSD   float64
+	ValueBRL, ValueUSD float64
+	PnLBRL, PnLUSD     float64
+	PnLPct             float64 // sobre o custo em BRL
+	Allocation         float64 // % do valor total da carteira
+	Priced             bool
+}
+
+// PortfolioView reúne os dados do template portfolio
+type PortfolioView struct {
+	Positions          []PositionView
+	CostBRL, CostUSD   float64 // custo das posições com cotação
+	ValueBRL, ValueUSD float64
+	PnLBRL, PnLUSD     float64
+	PnLPct             float64
+	Unpriced           []string // moedas sem cotação no momento (fora dos totais)
+}
+
+// PortfolioTransactionView é uma linha do template portfolio_transactions
+type PortfolioTransactionView struct {
+	store.PortfolioTransaction
+	Sell bool
+	Abs  float64 // quantidade sem sinal
+}
+
+// position acumula as transações de uma moeda pelo custo médio: vendas reduzem a quantidade
+// e o custo na proporção vendida, sem alterar o custo médio do que sobrou
+type position struct {
+	coinID, symbol   string
+	quantity         float64
+	costBRL, costUSD float64
+}
+
+// AddPortfolioTransaction registra uma compra (ou venda, com sell=true) na carteira do usuário.
+// Sem preço, usa a cotação atual; o preço vale em BRL, salvo quando indicado usd/us$.
+func AddPortfolioTransaction(ctx context.Context, owner, args string, sell bool) (string, error) {
+	if store.DB == nil {
+		return "", fmt.Errorf("⚠️ Carteira indisponível: banco de dados desconectado.")
+	}
+
+	t, err := parsePortfolioTransaction(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	t.Owner = owner
+
+	if sell {
+		txs, err := store.ListPortfolioTransactions(ctx, owner)
+		if err != nil {
+			return "", err
+		}
+		held := 0.0
+		if p, ok := buildPositions(txs)[t.CoinID]; ok {
+			held = p.quantity
+		}
+		if t.Quantity > held+dustQuantity {
+			return "", fmt.Errorf("⚠️ Você tem %s %s na carteira — não dá para vender %s",
+				currentLocale().Number(held), strings.ToUpper(t.Symbol), currentLocale().Number(t.Quantity))
+		}
+		t.Quantity = -t.Quantity
+	}
+
+	id, err := store.AddPortfolioTransaction(ctx, t)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("💼 Transação #%d na carteira de %s: %+g %s a R$ %.2f", id, owner, t.Quantity, t.Symbol, t.PriceBRL)
+
+	action := "Compra"
+	if sell {
+		action = "Venda"
+	}
+	loc := currentLocale()
+	return fmt.Sprintf("✅ %s #%d registrada: %s %s a %s (%s)", action, id,
+		loc.Number(math.Abs(t.Quantity)), strings.ToUpper(t.Symbol), loc.Money(t.PriceBRL, "BRL"), loc.Money(t.PriceUSD, "USD")), nil
+}
+
+// Portfolio mostra as posições do usuário com valor atual, alocação e lucro/prejuízo em BRL e USD
+func Portfolio(ctx context.Context, owner string) (string, error) {
+	if store.DB == nil {
+		return "", fmt.Errorf("⚠️ Carteira indisponível: banco de dados desconectado.")
+	}
+
+	txs, err := store.ListPortfolioTransactions(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+	positions := buildPositions(txs)
+
+	ids := make([]string, 0, len(positions))
+	for id := range positions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	quotes := map[string]MarketQuote{}
+	if len(ids) > 0 {
+		batch, err := GetMarketsBatch(ids)
+		if err != nil {
+			log.Printf("⚠️ Carteira sem cotações: %v", err)
+		}
+		for _, q := range batch {
+			quotes[q.ID] = q
+		}
+	}
+
+	var view PortfolioView
+	for _, id := range ids {
+		p := positions[id]
+		pv := PositionView{
+			Symbol:   strings.ToUpper(p.symbol),
+			Name:     coinDir.coin(id).Name,
+			Quantity: p.quantity,
+			AvgBRL:   p.costBRL / p.quantity,
+			AvgUSD:   p.costUSD / p.quantity,
+			CostBRL:  p.costBRL,
+			CostUSD:  p.costUSD,
+		}
+		if q, ok := quotes[id]; ok && q.PriceBRL > 0 {
+			pv.Priced = true
+			pv.PriceBRL, pv.PriceUSD = q.PriceBRL, q.PriceUSD
+			pv.ValueBRL, pv.ValueUSD = p.quantity*q.PriceBRL, p.quantity*q.PriceUSD
+			pv.PnLBRL, pv.PnLUSD = pv.ValueBRL-pv.CostBRL, pv.ValueUSD-pv.CostUSD
+			pv.PnLPct = percentChange(pv.CostBRL, pv.ValueBRL)
+
+			view.CostBRL += pv.CostBRL
+			view.CostUSD += pv.CostUSD
+			view.ValueBRL += pv.ValueBRL
+			view.ValueUSD += pv.ValueUSD
+		} else {
+			view.Unpriced = append(view.Unpriced, pv.Symbol)
+		}
+		view.Positions = append(view.Positions, pv)
+	}
+
+	for i := range view.Positions {
+		if view.ValueBRL > 0 {
+			view.Positions[i].Allocation = view.Positions[i].ValueBRL / view.ValueBRL * 100
+		}
+	}
+	sort.SliceStable(view.Positions, func(i, j int) bool { return view.Positions[i].ValueBRL > view.Positions[j].ValueBRL })
+
+	view.PnLBRL, view.PnLUSD = view.ValueBRL-view.CostBRL, view.ValueUSD-view.CostUSD
+	view.PnLPct = percentChange(view.CostBRL, view.ValueBRL)
+	return RenderTemplate("portfolio", view)
+}
+
+// PortfolioTransactions lista as transações do usuário, com os IDs usados em !carteira remover
+func PortfolioTransactions(ctx context.Context, owner string) (string, error) {
+	if store.DB == nil {
+		return "", fmt.Errorf("⚠️ Carteira indisponível: banco de dados desconectado.")
+	}
+
+	txs, err := store.ListPortfolioTransactions(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+	views := make([]PortfolioTransactionView, 0, len(txs))
+	for _, t := range txs {
+		views = append(views, PortfolioTransactionView{PortfolioTransaction: t, Sell: t.Quantity < 0, Abs: math.Abs(t.Quantity)})
+	}
+	return RenderTemplate("portfolio_transactions", views)
+}
+
+// DeletePortfolioTransaction apaga uma transação da carteira pelo ID exibido em !carteira historico
+func DeletePortfolioTransaction(ctx context.Context, owner, arg string) error {
+	if store.DB == nil {
+		return fmt.Errorf("⚠️ Carteira indisponível: banco de dados desconectado.")
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(arg), "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("⚠️ Uso: !carteira remover <id> (veja os IDs em !carteira historico)")
+	}
+	return store.DeletePortfolioTransaction(ctx, owner, id)
+}
+
+// buildPositions consolida as transações (em ordem cronológica) em posições por moeda.
+// Moedas totalmente vendidas ficam de fora.
+func buildPositions(txs []store.PortfolioTransaction) map[string]*position {
+	positions := map[string]*position{}
+	for _, t := range txs {
+		p, ok := positions[t.CoinID]
+		if !ok {
+			p = &position{coinID: t.CoinID, symbol: t.Symbol}
+			positions[t.CoinID] = p
+		}
+
+		if t.Quantity > 0 {
+			p.quantity += t.Quantity
+			p.costBRL += t.Quantity * t.PriceBRL
+			p.costUSD += t.Quantity * t.PriceUSD
+			continue
+		}
+
+		// Venda: se uma compra anterior foi apagada, a venda zera a posição em vez de deixá-la negativa
+		sold := math.Min(-t.Quantity, p.quantity)
+		if p.quantity > 0 {
+			p.costBRL -= p.costBRL * sold / p.quantity
+			p.costUSD -= p.costUSD * sold / p.quantity
+		}
+		p.quantity -= sold
+		if p.quantity < dustQuantity {
+			p.quantity, p.costBRL, p.costUSD = 0, 0, 0
+		}
+	}
+
+	for id, p := range positions {
+		if p.quantity < dustQuantity {
+			delete(positions, id)
+		}
+	}
+	return positions
+}
+
+// parsePortfolioTransaction interpreta "<moeda> <quantidade> [@|por|a <preço>] [brl|usd]"
+func parsePortfolioTransaction(ctx context.Context, args string) (store.PortfolioTransaction, error) {
+	var terms []string
+	for _, f := range strings.Fields(strings.ReplaceAll(strings.ToLower(args), "@", " @ ")) {
+		if f != "@" && f != "por" && f != "a" && f != "de" {
+			terms = append(terms, f)
+		}
+	}
+	if len(terms) < 2 || len(terms) > 4 {
+		return store.PortfolioTransaction{}, fmt.Errorf("%s", portfolioUsage)
+	}
+
+	id, err := resolveCoin(terms[0])
+	if err != nil {
+		return store.PortfolioTransaction{}, err
+	}
+	quantity, err := utils.ParseAmount(terms[1])
+	if err != nil || quantity <= 0 {
+		return store.PortfolioTransaction{}, fmt.Errorf("⚠️ Quantidade inválida '%s'\n%s", terms[1], portfolioUsage)
+	}
+
+	price, currency := 0.0, "brl"
+	for _, term := range terms[2:] {
+		if c := alertCurrencies[term]; c != "" {
+			currency = c
+			continue
+		}
+		if price > 0 {
+			return store.PortfolioTransaction{}, fmt.Errorf("⚠️ Termo '%s' não reconhecido\n%s", term, portfolioUsage)
+		}
+		var prefix string
+		if price, prefix, err = parseMoney(term); err != nil || price <= 0 {
+			return store.PortfolioTransaction{}, fmt.Errorf("⚠️ Preço inválido '%s'\n%s", term, portfolioUsage)
+		}
+		if prefix != "" {
+			currency = prefix
+		}
+	}
+
+	t := store.PortfolioTransaction{CoinID: id, Symbol: coinDir.coin(id).Symbol, Quantity: quantity}
+
+	// Sem preço informado, vale a cotação atual
+	if price == 0 {
+		q, err := GetMarketQuote(id)
+		if err != nil {
+			return store.PortfolioTransaction{}, err
+		}
+		t.Symbol, t.PriceBRL, t.PriceUSD = q.Symbol, q.PriceBRL, q.PriceUSD
+		return t, nil
+	}
+
+	rate, err := usdToBRL(ctx)
+	if err != nil || rate <= 0 {
+		return store.PortfolioTransaction{}, fmt.Errorf("❌ Câmbio indisponível para converter o preço: %v", err)
+	}
+	if currency == "usd" {
+		t.PriceUSD, t.PriceBRL = price, price*rate
+	} else {
+		t.PriceBRL, t.PriceUSD = price, price/rate
+	}
+	if t.Symbol == "" {
+		t.Symbol = id
+	}
+	return t, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faysk/whatsapp-bot/store"
+)
+
+func TestBuildPositions(t *testing.T) {
+	buy := func(coin string, qty, priceBRL float64) store.PortfolioTransaction {
+		return store.PortfolioTransaction{CoinID: coin, Symbol: coin, Quantity: qty, PriceBRL: priceBRL, PriceUSD: priceBRL / 5}
+	}
+	sell := func(coin string, qty, priceBRL float64) store.PortfolioTransaction {
+		return buy(coin, -qty, priceBRL)
+	}
+
+	type want struct{ quantity, costBRL, costUSD float64 }
+	tests := []struct {
+		name string
+		txs  []store.PortfolioTransaction
+		want map[string]want
+	}{
+		{
+			name: "vazia",
+			want: map[string]want{},
+		},
+		{
+			name: "custo médio de duas compras",
+			txs:  []store.PortfolioTransaction{buy("bitcoin", 1, 100), buy("bitcoin", 1, 200)},
+			want: map[string]want{"bitcoin": {2, 300, 60}},
+		},
+		{
+			name: "venda parcial mantém o custo médio",
+			txs:  []store.PortfolioTransaction{buy("bitcoin", 2, 100), buy("bitcoin", 2, 200), sell("bitcoin", 1, 500)},
+			want: map[string]want{"bitcoin": {3, 450, 90}},
+		},
+		{
+			name: "venda total remove a moeda",
+			txs:  []store.PortfolioTransaction{buy("ethereum", 1.5, 10), sell("ethereum", 1.5, 20), buy("bitcoin", 1, 100)},
+			want: map[string]want{"bitcoin": {1, 100, 20}},
+		},
+		{
+			name: "venda maior que o saldo zera em vez de negativar",
+			txs:  []store.PortfolioTransaction{buy("bitcoin", 1, 100), sell("bitcoin", 3, 100), buy("bitcoin", 1, 300)},
+			want: map[string]want{"bitcoin": {1, 300, 60}},
+		},
+		{
+			name: "venda sem compra anterior é ignorada",
+			txs:  []store.PortfolioTransaction{sell("solana", 2, 100)},
+			want: map[string]want{},
+		},
+		{
+			name: "resíduo de arredondamento conta como zerado",
+			txs:  []store.PortfolioTransaction{buy("bitcoin", 0.1, 100), buy("bitcoin", 0.2, 100), sell("bitcoin", 0.3, 100)},
+			want: map[string]want{},
+		},
+	}
+
+	const eps = 1e-9
+	for _, tt := range tests {
+		got := buildPositions(tt.txs)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %d posição(ões), quero %d", tt.name, len(got), len(tt.want))
+			continue
+		}
+		for id, w := range tt.want {
+			p, ok := got[id]
+			if !ok {
+				t.Errorf("%s: sem posição de %s", tt.name, id)
+				continue
+			}
+			if math.Abs(p.quantity-w.quantity) > eps || math.Abs(p.costBRL-w.costBRL) > eps || math.Abs(p.costUSD-w.costUSD) > eps {
+				t.Errorf("%s: %s = {%v %v %v}, quero %+v", tt.name, id, p.quantity, p.costBRL, p.costUSD, w)
+			}
+		}
+	}
+}
@@ -0,0 +1,462 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
+	"github.com/faysk/whatsapp-bot/utils"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const maxAlertsPerChat = 20
+
+// alertUsage é a ajuda exibida quando o !alerta não é entendido
+const alertUsage = "⚠️ Uso: !alerta <moeda> <acima|abaixo> <valor> [brl|usd] [recorrente] [urgente]\n" +
+	"ou: !alerta <moeda> <±|+|->%<variação> [janela] [pausa <tempo>] [urgente]\n" +
+	"Ex: !alerta btc > 600000 brl | !alerta eth ±5% 1h | !alerta btc cai 10% 24h pausa 6h"
+
+const (
+	minMoveWindow   = 5 * time.Minute
+	maxMoveCooldown = 7 * 24 * time.Hour
+	defaultWindow   = time.Hour
+)
+
+var (
+	alertOperators = strings.NewReplacer(">=", " > ", "<=", " < ", ">", " > ", "<", " < ")
+
+	alertDirections = map[string]string{
+		">": "above", "acima": "above", "above": "above", "maior": "above",
+		"<": "below", "abaixo": "below", "below": "below", "menor": "below",
+	}
+
+	alertCurrencies = map[string]string{
+		"brl": "brl", "real": "brl", "reais": "brl", "r$": "brl",
+		"usd": "usd", "dolar": "usd", "dólar": "usd", "dolares": "usd", "dólares": "usd", "us$": "usd",
+	}
+
+	alertRecurring = map[string]bool{"recorrente": true, "sempre": true, "recurring": true}
+
+	// alertUrgent marca o alerta para ser avisado mesmo no horário de silêncio (!silencio)
+	alertUrgent = map[string]bool{"urgente": true, "urgent": true}
+
+	alertFillers = map[string]bool{"de": true, "em": true, "a": true, "que": true, "até": true, "ate": true}
+
+	// moveDirections são as palavras aceitas antes da variação (ex: "btc cai 10% 24h")
+	moveDirections = map[string]string{
+		"sobe": "above", "subir": "above", "alta": "above", "up": "above",
+		"cai": "below", "cair": "below", "queda": "below", "down": "below",
+		"move": "both", "varia": "both", "variar": "both", "oscila": "both",
+	}
+
+	moveCooldownWords = map[string]bool{"pausa": true, "cooldown": true, "intervalo": true}
+
+	alertDuration = regexp.MustCompile(`^(\d+)(m|min|h|d)$`)
+)
+
+// AlertView reúne os dados de um alerta exibidos nos templates price_alerts e price_alert
+type AlertView struct {
+	ID        int64
+	Symbol    string
+	Name      string
+	Move      bool // alerta de variação percentual
+	Above     bool
+	Both      bool
+	Target    float64
+	Currency  string
+	Recurring bool
+	Urgent    bool // avisado mesmo no horário de silêncio
+	Armed     bool
+	Window    string // janela dos alertas de variação (ex: 1h)
+	Cooldown  string
+	Change    float64 // variação (em módulo) que disparou o alerta
+	Reference float64 // mínima (alta) ou máxima (queda) da janela, na moeda do alerta
+	Price     float64 // preço atual (0 quando indisponível)
+	Reached   bool    // o preço atual já atende ao alerta
+	Mention   string  // @número do criador, em grupos
+	At        time.Time
+}
+
+// CreatePriceAlert interpreta e grava um alerta (ex: "btc > 600000 brl recorrente") para a conversa
+func CreatePriceAlert(ctx context.Context, chat, createdBy, args string) (string, error) {
+	if store.DB == nil {
+		return "", fmt.Errorf("⚠️ Alertas indisponíveis: banco de dados desconectado.")
+	}
+
+	alert, err := parseAlert(args)
+	if err != nil {
+		return "", err
+	}
+	coin := coinDir.coin(alert.CoinID)
+	alert.Chat = chat
+	alert.CreatedBy = createdBy
+	alert.Symbol = coin.Symbol
+
+	n, err := store.CountPriceAlerts(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	if n >= maxAlertsPerChat {
+		return "", fmt.Errorf("⚠️ Limite de %d alertas por conversa atingido. Remova algum com !alerta remover <id>", maxAlertsPerChat)
+	}
+
+	id, err := store.CreatePriceAlert(ctx, alert)
+	if err != nil {
+		return "", err
+	}
+	alert.ID, alert.Armed = id, true
+	log.Printf("🔔 Alerta #%d criado em %s: %s %s %.8g %s", id, chat, alert.CoinID, alert.Direction, alert.Target, alert.Currency)
+
+	view := alertView(alert, coin.Name)
+	if q, err := GetMarketQuote(alert.CoinID); err == nil {
+		view.Price = alertPrice(q, alert.Currency)
+		view.Reached = alert.Kind == store.AlertPrice && alertReached(alert, view.Price)
+	}
+	return RenderTemplate("price_alert_created", view)
+}
+
+// ListPriceAlerts mostra os alertas da conversa
+func ListPriceAlerts(ctx context.Context, chat string) (string, error) {
+	if store.DB == nil {
+		return "", fmt.Errorf("⚠️ Alertas indisponíveis: banco de dados desconectado.")
+	}
+
+	alerts, err := store.ListPriceAlerts(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+
+	views := make([]AlertView, 0, len(alerts))
+	for _, a := range alerts {
+		views = append(views, alertView(a, coinDir.coin(a.CoinID).Name))
+	}
+	return RenderTemplate("price_alerts", views)
+}
+
+// DeletePriceAlert remove um alerta da conversa pelo ID exibido em !alerta lista
+func DeletePriceAlert(ctx context.Context, chat, arg string) error {
+	if store.DB == nil {
+		return fmt.Errorf("⚠️ Alertas indisponíveis: banco de dados desconectado.")
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(arg), "#"), 10, 64)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("⚠️ Uso: !alerta remover <id> (veja os IDs em !alerta lista)")
+	}
+	return store.DeletePriceAlert(ctx, chat, id)
+}
+
+// checkPriceAlerts avalia os alertas com as cotações da verificação do monitor e avisa a
+// conversa de cada alerta disparado (em grupos, marcando quem o criou)
+func checkPriceAlerts(ctx context.Context, m transport.Messenger, alerts []store.PriceAlert, byID map[string]MarketQuote) {
+	for _, a := range alerts {
+		q, ok := byID[a.CoinID]
+		if !ok {
+			continue
+		}
+		price := alertPrice(q, a.Currency)
+		if price <= 0 {
+			continue
+		}
+		if a.Kind == store.AlertMove {
+			checkMoveAlert(ctx, m, a, q, price)
+			continue
+		}
+
+		hit := alertReached(a, price)
+		switch {
+		case hit && a.Armed:
+			if err := store.FirePriceAlert(ctx, a); err != nil {
+				log.Printf("⚠️ %v", err)
+				continue
+			}
+			view := alertView(a, q.Name)
+			view.Price = price
+			notifyPriceAlert(ctx, m, a, view, q.FetchedAt)
+		case !hit && !a.Armed:
+			if err := store.RearmPriceAlert(ctx, a.ID); err != nil {
+				log.Printf("⚠️ %v", err)
+			}
+		}
+	}
+}
+
+// checkMoveAlert dispara o alerta de variação quando a alta desde a mínima ou a queda desde a
+// máxima da janela atinge o percentual, respeitando o cooldown desde o último aviso
+func checkMoveAlert(ctx context.Context, m transport.Messenger, a store.PriceAlert, q MarketQuote, price float64) {
+	if a.LastFiredAt != nil && time.Since(*a.LastFiredAt) < a.Cooldown {
+		return
+	}
+	st, ok := priceWindows.stats(a.CoinID, a.Window)
+	if !ok {
+		return
+	}
+
+	rise := a.Direction != "below" && st.Rise >= a.Target
+	drop := a.Direction != "above" && -st.Drop >= a.Target
+	if rise && drop {
+		// Nas duas direções ao mesmo tempo, vale a maior variação
+		rise = st.Rise >= -st.Drop
+		drop = !rise
+	}
+	if !rise && !drop {
+		return
+	}
+
+	if err := store.FirePriceAlert(ctx, a); err != nil {
+		log.Printf("⚠️ %v", err)
+		return
+	}
+
+	view := alertView(a, q.Name)
+	view.Price = price
+	view.Above = rise
+	scale := price / st.Last // converte os valores em USD da janela para a moeda do alerta
+	if rise {
+		view.Change, view.Reference = st.Rise, st.Low*scale
+	} else {
+		view.Change, view.Reference = -st.Drop, st.High*scale
+	}
+	notifyPriceAlert(ctx, m, a, view, q.FetchedAt)
+}
+
+// notifyPriceAlert avisa a conversa que criou o alerta
+func notifyPriceAlert(ctx context.Context, m transport.Messenger, a store.PriceAlert, view AlertView, at time.Time) {
+	chat, err := types.ParseJID(a.Chat)
+	if err != nil {
+		log.Printf("⚠️ Alerta #%d com conversa inválida %q: %v", a.ID, a.Chat, err)
+		return
+	}
+	view.At = at
+
+	var mentions []types.JID
+	if chat.Server == types.GroupServer {
+		if creator, err := types.ParseJID(a.CreatedBy); err == nil {
+			mentions = append(mentions, creator)
+			view.Mention = "@" + creator.User
+		}
+	}
+
+	msg, err := RenderTemplate("price_alert", view)
+	if err != nil {
+		log.Printf("⚠️ Alerta #%d: %v", a.ID, err)
+		return
+	}
+	log.Printf("🔔 Alerta #%d disparado: %s a %.8g %s", a.ID, a.CoinID, view.Price, a.Currency)
+	SendProactive(ctx, m, chat, Text{Body: msg, Mentions: mentions}, a.Urgent)
+}
+
+// parseAlert interpreta "<moeda> <acima|abaixo|>|<> <valor> [brl|usd] [recorrente]"
+// ou, nos alertas de variação, "<moeda> [sobe|cai|move] <±|+|->%<variação> [janela] [pausa <tempo>]"
+func parseAlert(args string) (store.PriceAlert, error) {
+	var terms []string
+	for _, f := range strings.Fields(alertOperators.Replace(strings.ToLower(args))) {
+		if !alertFillers[f] {
+			terms = append(terms, f)
+		}
+	}
+	if len(terms) < 2 {
+		return store.PriceAlert{}, fmt.Errorf("%s", alertUsage)
+	}
+
+	id, err := resolveCoin(terms[0])
+	if err != nil {
+		return store.PriceAlert{}, err
+	}
+
+	if moveDirections[terms[1]] != "" || strings.HasSuffix(terms[1], "%") {
+		return parseMoveAlert(id, terms[1:])
+	}
+	if len(terms) < 3 {
+		return store.PriceAlert{}, fmt.Errorf("%s", alertUsage)
+	}
+
+	direction, ok := alertDirections[terms[1]]
+	if !ok {
+		return store.PriceAlert{}, fmt.Errorf("%s", alertUsage)
+	}
+
+	alert := store.PriceAlert{Kind: store.AlertPrice, CoinID: id, Direction: direction, Currency: "brl"}
+
+	target, currency, err := parseMoney(terms[2])
+	if err != nil {
+		return store.PriceAlert{}, err
+	}
+	if currency != "" {
+		alert.Currency = currency
+	}
+	if target <= 0 {
+		return store.PriceAlert{}, fmt.Errorf("⚠️ O valor do alerta precisa ser maior que zero")
+	}
+	alert.Target = target
+
+	for _, t := range terms[3:] {
+		switch {
+		case alertRecurring[t]:
+			alert.Recurring = true
+		case alertUrgent[t]:
+			alert.Urgent = true
+		case alertCurrencies[t] != "":
+			alert.Currency = alertCurrencies[t]
+		default:
+			return store.PriceAlert{}, fmt.Errorf("⚠️ Termo '%s' não reconhecido\n%s", t, alertUsage)
+		}
+	}
+	return alert, nil
+}
+
+// parseMoney lê um valor digitado com ou sem símbolo (600000, 600.000, us$2500), devolvendo
+// a moeda indicada pelo prefixo (us$/$ → usd, r$ → brl) ou "" quando não houver
+func parseMoney(value string) (amount float64, currency string, err error) {
+	switch {
+	case strings.HasPrefix(value, "us$"), strings.HasPrefix(value, "$"):
+		currency = "usd"
+	case strings.HasPrefix(value, "r$"):
+		currency = "brl"
+	}
+	value = strings.TrimLeft(value, "ur$s")
+	amount, err = utils.ParseAmount(value)
+	return amount, currency, err
+}
+
+// parseMoveAlert interpreta a parte do alerta de variação após a moeda (ex: "±5% 1h", "cai 10% 24h pausa 6h").
+// Sem janela, vale 1h; sem pausa, o cooldown é a própria janela.
+func parseMoveAlert(id string, terms []string) (store.PriceAlert, error) {
+	alert := store.PriceAlert{Kind: store.AlertMove, CoinID: id, Direction: "both", Currency: "brl", Recurring: true}
+
+	if dir, ok := moveDirections[terms[0]]; ok {
+		alert.Direction = dir
+		terms = terms[1:]
+	}
+	if len(terms) == 0 || !strings.HasSuffix(terms[0], "%") {
+		return store.PriceAlert{}, fmt.Errorf("%s", alertUsage)
+	}
+
+	value := strings.TrimSuffix(terms[0], "%")
+	switch {
+	case strings.HasPrefix(value, "±"), strings.HasPrefix(value, "+-"):
+		value = strings.TrimLeft(strings.TrimPrefix(value, "±"), "+-")
+	case strings.HasPrefix(value, "+"):
+		alert.Direction, value = "above", value[1:]
+	case strings.HasPrefix(value, "-"):
+		alert.Direction, value = "below", value[1:]
+	}
+	pct, err := utils.ParseAmount(value)
+	if err != nil {
+		return store.PriceAlert{}, err
+	}
+	if pct <= 0 || pct > 100 {
+		return store.PriceAlert{}, fmt.Errorf("⚠️ A variação do alerta precisa estar entre 0 e 100%%")
+	}
+	alert.Target = pct
+
+	for i := 1; i < len(terms); i++ {
+		t := terms[i]
+		switch {
+		case moveCooldownWords[t] && i+1 < len(terms):
+			d, ok := parseAlertDuration(terms[i+1])
+			if !ok {
+				return store.PriceAlert{}, fmt.Errorf("⚠️ Pausa inválida '%s' (use ex: 30m, 2h, 1d)", terms[i+1])
+			}
+			alert.Cooldown = d
+			i++
+		case alertCurrencies[t] != "":
+			alert.Currency = alertCurrencies[t]
+		case alertRecurring[t]:
+			// alertas de variação já são recorrentes
+		case alertUrgent[t]:
+			alert.Urgent = true
+		default:
+			d, ok := parseAlertDuration(t)
+			if !ok {
+				return store.PriceAlert{}, fmt.Errorf("⚠️ Termo '%s' não reconhecido\n%s", t, alertUsage)
+			}
+			alert.Window = d
+		}
+	}
+
+	if alert.Window == 0 {
+		alert.Window = defaultWindow
+	}
+	if alert.Window < minMoveWindow || alert.Window > maxMoveWindow {
+		return store.PriceAlert{}, fmt.Errorf("⚠️ A janela precisa ficar entre %s e %s", formatWindow(minMoveWindow), formatWindow(maxMoveWindow))
+	}
+	if alert.Cooldown == 0 {
+		alert.Cooldown = alert.Window
+	}
+	if alert.Cooldown < minMoveWindow || alert.Cooldown > maxMoveCooldown {
+		return store.PriceAlert{}, fmt.Errorf("⚠️ A pausa precisa ficar entre %s e %s", formatWindow(minMoveWindow), formatWindow(maxMoveCooldown))
+	}
+	return alert, nil
+}
+
+// parseAlertDuration aceita durações curtas como 30m, 45min, 4h e 1d
+func parseAlertDuration(s string) (time.Duration, bool) {
+	m := alertDuration.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	n, _ := strconv.Atoi(m[1])
+	unit := time.Minute
+	switch m[2] {
+	case "h":
+		unit = time.Hour
+	case "d":
+		unit = 24 * time.Hour
+	}
+	return time.Duration(n) * unit, n > 0
+}
+
+// formatWindow exibe a duração no mesmo formato aceito pelo comando (30min, 4h, 1d)
+func formatWindow(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dmin", d/time.Minute)
+	}
+}
+
+func alertView(a store.PriceAlert, name string) AlertView {
+	if name == "" {
+		name = strings.ToUpper(a.Symbol)
+	}
+	return AlertView{
+		ID:        a.ID,
+		Symbol:    a.Symbol,
+		Name:      name,
+		Move:      a.Kind == store.AlertMove,
+		Above:     a.Direction == "above",
+		Both:      a.Direction == "both",
+		Target:    a.Target,
+		Currency:  strings.ToUpper(a.Currency),
+		Recurring: a.Recurring,
+		Urgent:    a.Urgent,
+		Armed:     a.Armed,
+		Window:    formatWindow(a.Window),
+		Cooldown:  formatWindow(a.Cooldown),
+	}
+}
+
+// alertReached informa se o preço atende à condição do alerta
+func alertReached(a store.PriceAlert, price float64) bool {
+	if a.Direction == "below" {
+		return price <= a.Target
+	}
+	return price >= a.Target
+}
+
+// alertPrice devolve o preço da cotação na moeda do alerta
+func alertPrice(q MarketQuote, currency string) float64 {
+	if currency == "usd" {
+		return q.PriceUSD
+	}
+	return q.PriceBRL
+}
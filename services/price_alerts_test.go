@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/store"
+)
+
+func TestParseAlert(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    store.PriceAlert
+		wantErr bool
+	}{
+		{
+			in:   "btc > 600000",
+			want: store.PriceAlert{Kind: store.AlertPrice, CoinID: "bitcoin", Direction: "above", Target: 600000, Currency: "brl"},
+		},
+		{
+			in:   "btc acima de 600.000 brl",
+			want: store.PriceAlert{Kind: store.AlertPrice, CoinID: "bitcoin", Direction: "above", Target: 600000, Currency: "brl"},
+		},
+		{
+			in:   "eth<2500 usd recorrente",
+			want: store.PriceAlert{Kind: store.AlertPrice, CoinID: "ethereum", Direction: "below", Target: 2500, Currency: "usd", Recurring: true},
+		},
+		{
+			in:   "eth abaixo us$2.500 urgente",
+			want: store.PriceAlert{Kind: store.AlertPrice, CoinID: "ethereum", Direction: "below", Target: 2500, Currency: "usd", Urgent: true},
+		},
+		{
+			in:   "btc >= 0,5",
+			want: store.PriceAlert{Kind: store.AlertPrice, CoinID: "bitcoin", Direction: "above", Target: 0.5, Currency: "brl"},
+		},
+		{
+			in:   "eth ±5%",
+			want: store.PriceAlert{Kind: store.AlertMove, CoinID: "ethereum", Direction: "both", Target: 5, Currency: "brl", Recurring: true, Window: time.Hour, Cooldown: time.Hour},
+		},
+		{
+			in:   "btc cai 10% 24h pausa 6h",
+			want: store.PriceAlert{Kind: store.AlertMove, CoinID: "bitcoin", Direction: "below", Target: 10, Currency: "brl", Recurring: true, Window: 24 * time.Hour, Cooldown: 6 * time.Hour},
+		},
+		{
+			in:   "btc +2,5% 30min urgente",
+			want: store.PriceAlert{Kind: store.AlertMove, CoinID: "bitcoin", Direction: "above", Target: 2.5, Currency: "brl", Recurring: true, Window: 30 * time.Minute, Cooldown: 30 * time.Minute, Urgent: true},
+		},
+		{in: "btc", wantErr: true},
+		{in: "btc > ", wantErr: true},
+		{in: "btc entre 500", wantErr: true},
+		{in: "btc > 0", wantErr: true},
+		{in: "btc > -5", wantErr: true},
+		{in: "btc > inf", wantErr: true},
+		{in: "btc > 600000 talvez", wantErr: true},
+		{in: "moedainexistente > 10", wantErr: true},
+		{in: "eth ±150%", wantErr: true},
+		{in: "eth ±5% 1min", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAlert(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAlert(%q) erro = %v, quero erro = %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseAlert(%q) = %+v, quero %+v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// maxMoveWindow é a maior janela aceita nos alertas de variação (e o quanto de histórico é mantido)
+const maxMoveWindow = 24 * time.Hour
+
+// priceSample é um preço (USD) observado pelo monitor
+type priceSample struct {
+	at    time.Time
+	price float64
+}
+
+// priceWindows guarda, em memória, o histórico recente de preços por moeda, alimentado a cada
+// verificação do monitor. Depois de reiniciar, o histórico recomeça vazio e os alertas de
+// variação só passam a valer conforme a janela vai sendo preenchida.
+var priceWindows = &priceHistory{samples: map[string][]priceSample{}}
+
+type priceHistory struct {
+	mu      sync.Mutex
+	samples map[string][]priceSample // id → amostras em ordem cronológica
+}
+
+// record adiciona uma amostra e descarta as mais antigas que maxMoveWindow.
+// Cotações repetidas (mesmo horário, vindas do cache) são ignoradas.
+func (h *priceHistory) record(id string, price float64, at time.Time) {
+	if price <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	list := h.samples[id]
+	if n := len(list); n > 0 && !at.After(list[n-1].at) {
+		return
+	}
+	list = append(list, priceSample{at: at, price: price})
+
+	cutoff := at.Add(-maxMoveWindow)
+	drop := 0
+	for drop < len(list) && list[drop].at.Before(cutoff) {
+		drop++
+	}
+	h.samples[id] = list[drop:]
+}
+
+// stats compara o último preço com a mínima e a máxima da janela: Rise é a alta desde a
+// mínima e Drop é a queda desde a máxima (negativa), ambas em %. ok=false sem amostras suficientes.
+func (h *priceHistory) stats(id string, window time.Duration) (m windowStats, ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	list := h.samples[id]
+	if len(list) < 2 {
+		return windowStats{}, false
+	}
+
+	last := list[len(list)-1]
+	cutoff := last.at.Add(-window)
+	m = windowStats{Last: last.price, Low: last.price, High: last.price}
+	count := 0
+	for _, s := range list {
+		if s.at.Before(cutoff) {
+			continue
+		}
+		count++
+		m.Low = min(m.Low, s.price)
+		m.High = max(m.High, s.price)
+	}
+	if count < 2 {
+		return windowStats{}, false
+	}
+
+	m.Rise = percentChange(m.Low, m.Last)
+	m.Drop = percentChange(m.High, m.Last)
+	return m, true
+}
+
+// windowStats resume a janela de preços de uma moeda (valores em USD)
+type windowStats struct {
+	Last, Low, High float64
+	Rise, Drop      float64
+}
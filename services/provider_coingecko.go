@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const coinDetailAPI = "https://api.coingecko.com/api/v3/coins/%s?localization=false&tickers=false&market_data=true&community_data=false&developer_data=false&sparkline=false"
+
+// coingeckoProvider consulta /coins/{id}: a fonte mais completa (rank, variações, market cap e ATH)
+type coingeckoProvider struct{}
+
+func (coingeckoProvider) Name() string { return "CoinGecko" }
+
+func (p coingeckoProvider) Quote(ctx context.Context, coin CoinData) (MarketQuote, error) {
+	var data struct {
+		Name       string `json:"name"`
+		Symbol     string `json:"symbol"`
+		MarketData struct {
+			CurrentPrice             map[string]float64 `json:"current_price"`
+			ATH                      map[string]float64 `json:"ath"`
+			MarketCap                map[string]float64 `json:"market_cap"`
+			TotalVolume              map[string]float64 `json:"total_volume"`
+			MarketCapRank            int                `json:"market_cap_rank"`
+			PriceChangePercentage1h  map[string]float64 `json:"price_change_percentage_1h_in_currency"`
+			PriceChangePercentage24h float64            `json:"price_change_percentage_24h"`
+			PriceChangePercentage7d  float64            `json:"price_change_percentage_7d"`
+			PriceChangePercentage30d float64            `json:"price_change_percentage_30d"`
+			PriceChangePercentage1y  float64            `json:"price_change_percentage_1y"`
+		} `json:"market_data"`
+	}
+
+	if err := getJSON(ctx, p.Name(), fmt.Sprintf(coinDetailAPI, coin.ID), &data); err != nil {
+		return MarketQuote{}, err
+	}
+
+	md := data.MarketData
+	return MarketQuote{
+		Symbol:       data.Symbol,
+		Name:         data.Name,
+		Rank:         md.MarketCapRank,
+		PriceBRL:     md.CurrentPrice["brl"],
+		PriceUSD:     md.CurrentPrice["usd"],
+		Change1h:     md.PriceChangePercentage1h["brl"],
+		Change24h:    md.PriceChangePercentage24h,
+		Change7d:     md.PriceChangePercentage7d,
+		Change30d:    md.PriceChangePercentage30d,
+		Change1y:     md.PriceChangePercentage1y,
+		MarketCapBRL: md.MarketCap["brl"],
+		VolumeBRL:    md.TotalVolume["brl"],
+		ATHUSD:       md.ATH["usd"],
+	}, nil
+}
+
+// coingeckoGet consulta endpoints do CoinGecko fora da cadeia de cotações (gráficos, histórico, mercado),
+// respeitando a pausa após HTTP 429 e pausando a fonte quando o limite é atingido
+func coingeckoGet(ctx context.Context, url string, out any) error {
+	if until, paused := providerPausedUntil("CoinGecko"); paused {
+		return fmt.Errorf("⏳ CoinGecko indisponível até %s (limite de requisições)", until.Format("15:04:05"))
+	}
+
+	err := getJSON(ctx, "CoinGecko", url, out)
+	var rl *rateLimitError
+	if errors.As(err, &rl) {
+		pauseProvider("CoinGecko", rl.retryAfter)
+	}
+	return err
+}
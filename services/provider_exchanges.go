@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Corretoras cotam em USD/USDT; o preço em reais é convertido pela cotação do dólar (usdToBRL).
+
+// exchangeAssets são as moedas (ID do CoinGecko) cotadas nas corretoras e o código do par em USD.
+// O failover só vale para elas: um símbolo repetido por outro ativo traria o preço da moeda errada.
+// Stablecoins em dólar ficam de fora, pois não têm par USD próprio.
+var exchangeAssets = map[string]string{
+	"bitcoin": "BTC", "ethereum": "ETH", "ripple": "XRP", "binancecoin": "BNB", "solana": "SOL",
+	"cardano": "ADA", "dogecoin": "DOGE", "tron": "TRX", "avalanche-2": "AVAX", "chainlink": "LINK",
+	"polkadot": "DOT", "litecoin": "LTC", "bitcoin-cash": "BCH", "stellar": "XLM", "uniswap": "UNI",
+	"cosmos": "ATOM", "ethereum-classic": "ETC", "near": "NEAR", "aptos": "APT", "arbitrum": "ARB",
+	"shiba-inu": "SHIB", "sui": "SUI", "filecoin": "FIL", "aave": "AAVE", "algorand": "ALGO",
+}
+
+// exchangeSymbols mapeia os códigos que a corretora lista de outro jeito (ex: BTC → XBT na Kraken)
+var exchangeSymbols = map[string]map[string]string{
+	"Kraken": {"BTC": "XBT", "DOGE": "XDG"},
+}
+
+// exchangeSymbol devolve o código da moeda na corretora, só para as moedas de exchangeAssets
+func exchangeSymbol(provider string, coin CoinData) (string, error) {
+	symbol, ok := exchangeAssets[coin.ID]
+	if !ok {
+		return "", errUnsupportedCoin
+	}
+	if mapped, ok := exchangeSymbols[provider][symbol]; ok {
+		return mapped, nil
+	}
+	return symbol, nil
+}
+
+// exchangeQuote completa a cotação em USD com os valores em reais
+func exchangeQuote(ctx context.Context, coin CoinData, priceUSD, change24h, volumeUSD float64) (MarketQuote, error) {
+	if priceUSD <= 0 {
+		return MarketQuote{}, errUnsupportedCoin
+	}
+	rate, err := usdToBRL(ctx)
+	if err != nil {
+		return MarketQuote{}, err
+	}
+	return MarketQuote{
+		Symbol:    coin.Symbol,
+		Name:      coin.Name,
+		PriceUSD:  priceUSD,
+		PriceBRL:  priceUSD * rate,
+		Change24h: change24h,
+		VolumeBRL: volumeUSD * rate,
+	}, nil
+}
+
+//
+// ========== 🟡 Binance =========
+//
+
+type binanceProvider struct{}
+
+func (binanceProvider) Name() string { return "Binance" }
+
+func (p binanceProvider) Quote(ctx context.Context, coin CoinData) (MarketQuote, error) {
+	symbol, err := exchangeSymbol(p.Name(), coin)
+	if err != nil {
+		return MarketQuote{}, err
+	}
+
+	var data struct {
+		LastPrice          string `json:"lastPrice"`
+		PriceChangePercent string `json:"priceChangePercent"`
+		QuoteVolume        string `json:"quoteVolume"`
+	}
+	url := fmt.Sprintf("https://api.binance.com/api/v3/ticker/24hr?symbol=%sUSDT", symbol)
+	if err := getJSON(ctx, p.Name(), url, &data); err != nil {
+		return MarketQuote{}, err
+	}
+
+	return exchangeQuote(ctx, coin, parseFloat(data.LastPrice), parseFloat(data.PriceChangePercent), parseFloat(data.QuoteVolume))
+}
+
+//
+// ========== 🟣 Kraken =========
+//
+
+type krakenProvider struct{}
+
+func (krakenProvider) Name() string { return "Kraken" }
+
+func (p krakenProvider) Quote(ctx context.Context, coin CoinData) (MarketQuote, error) {
+	symbol, err := exchangeSymbol(p.Name(), coin)
+	if err != nil {
+		return MarketQuote{}, err
+	}
+
+	// c = [último preço, lote], v = [volume hoje, volume 24h] (na moeda base)
+	var data struct {
+		Error  []string `json:"error"`
+		Result map[string]struct {
+			C []string `json:"c"`
+			V []string `json:"v"`
+		} `json:"result"`
+	}
+	url := fmt.Sprintf("https://api.kraken.com/0/public/Ticker?pair=%sUSD", symbol)
+	if err := getJSON(ctx, p.Name(), url, &data); err != nil {
+		return MarketQuote{}, err
+	}
+	if len(data.Error) > 0 {
+		if strings.Contains(data.Error[0], "Unknown asset pair") {
+			return MarketQuote{}, errUnsupportedCoin
+		}
+		return MarketQuote{}, fmt.Errorf("❌ Kraken: %s", strings.Join(data.Error, ", "))
+	}
+
+	// O nome do par vem normalizado (ex: XXBTZUSD); só há um na resposta
+	for _, t := range data.Result {
+		if len(t.C) == 0 || len(t.V) < 2 {
+			break
+		}
+		price := parseFloat(t.C[0])
+		// O ticker da Kraken só traz a abertura do dia (00:00 UTC), não o preço de 24h atrás
+		q, err := exchangeQuote(ctx, coin, price, 0, parseFloat(t.V[1])*price)
+		q.NoChange24h = true
+		return q, err
+	}
+	return MarketQuote{}, errUnsupportedCoin
+}
+
+//
+// ========== 🔵 Coinbase =========
+//
+
+type coinbaseProvider struct{}
+
+func (coinbaseProvider) Name() string { return "Coinbase" }
+
+func (p coinbaseProvider) Quote(ctx context.Context, coin CoinData) (MarketQuote, error) {
+	symbol, err := exchangeSymbol(p.Name(), coin)
+	if err != nil {
+		return MarketQuote{}, err
+	}
+
+	var data struct {
+		Data struct {
+			Amount string `json:"amount"`
+		} `json:"data"`
+	}
+	url := fmt.Sprintf("https://api.coinbase.com/v2/prices/%s-USD/spot", symbol)
+	if err := getJSON(ctx, p.Name(), url, &data); err != nil {
+		return MarketQuote{}, err
+	}
+
+	// O preço spot da Coinbase não traz variação nem volume
+	q, err := exchangeQuote(ctx, coin, parseFloat(data.Data.Amount), 0, 0)
+	q.NoChange24h = true
+	return q, err
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExchangeSymbol(t *testing.T) {
+	tests := []struct {
+		provider string
+		coin     CoinData
+		want     string
+		wantErr  bool
+	}{
+		{"Binance", CoinData{ID: "bitcoin", Symbol: "btc"}, "BTC", false},
+		{"Kraken", CoinData{ID: "bitcoin", Symbol: "btc"}, "XBT", false},
+		{"Kraken", CoinData{ID: "dogecoin", Symbol: "doge"}, "XDG", false},
+		{"Coinbase", CoinData{ID: "ethereum", Symbol: "eth"}, "ETH", false},
+		// Mesmo símbolo, outro ativo: sem mapeamento explícito, não há failover
+		{"Binance", CoinData{ID: "spx6900", Symbol: "spx"}, "", true},
+		{"Binance", CoinData{ID: "batcat", Symbol: "btc"}, "", true},
+		{"Binance", CoinData{ID: "tether", Symbol: "usdt"}, "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := exchangeSymbol(tt.provider, tt.coin)
+		if tt.wantErr {
+			if !errors.Is(err, errUnsupportedCoin) {
+				t.Errorf("exchangeSymbol(%s, %s) erro = %v, quero errUnsupportedCoin", tt.provider, tt.coin.ID, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("exchangeSymbol(%s, %s) = %q, %v; quero %q", tt.provider, tt.coin.ID, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+)
+
+//
+// ========== 🟪 Yahoo Finance =========
+//
+
+type yahooProvider struct{}
+
+func (yahooProvider) Name() string { return "Yahoo" }
+
+func (p yahooProvider) Quote(ctx context.Context, inst Instrument) (InstrumentQuote, error) {
+	if inst.Yahoo == "" {
+		return InstrumentQuote{}, errUnsupportedCoin
+	}
+
+	var data struct {
+		Chart struct {
+			Result []struct {
+				Meta struct {
+					Currency           string  `json:"currency"`
+					ShortName          string  `json:"shortName"`
+					LongName           string  `json:"longName"`
+					RegularMarketPrice float64 `json:"regularMarketPrice"`
+					ChartPreviousClose float64 `json:"chartPreviousClose"`
+					PreviousClose      float64 `json:"previousClose"`
+					DayHigh            float64 `json:"regularMarketDayHigh"`
+					DayLow             float64 `json:"regularMarketDayLow"`
+					RegularMarketTime  int64   `json:"regularMarketTime"`
+				} `json:"meta"`
+			} `json:"result"`
+		} `json:"chart"`
+	}
+	u := fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s?interval=1d&range=1d", url.PathEscape(inst.Yahoo))
+	if err := getJSON(ctx, p.Name(), u, &data); err != nil {
+		return InstrumentQuote{}, err
+	}
+	if len(data.Chart.Result) == 0 || data.Chart.Result[0].Meta.RegularMarketPrice <= 0 {
+		return InstrumentQuote{}, errUnsupportedCoin
+	}
+
+	m := data.Chart.Result[0].Meta
+	prev := m.PreviousClose
+	if prev <= 0 {
+		prev = m.ChartPreviousClose
+	}
+	name := m.LongName
+	if name == "" {
+		name = m.ShortName
+	}
+
+	return InstrumentQuote{
+		Instrument: Instrument{Name: name},
+		Price:      m.RegularMarketPrice,
+		Currency:   strings.ToUpper(m.Currency),
+		Change:     percentChange(prev, m.RegularMarketPrice),
+		DayHigh:    m.DayHigh,
+		DayLow:     m.DayLow,
+		MarketTime: unixTime(m.RegularMarketTime),
+	}, nil
+}
+
+//
+// ========== 🟩 brapi (B3) =========
+//
+
+type brapiProvider struct{}
+
+func (brapiProvider) Name() string { return "brapi" }
+
+func (p brapiProvider) Quote(ctx context.Context, inst Instrument) (InstrumentQuote, error) {
+	if inst.Brapi == "" {
+		return InstrumentQuote{}, errUnsupportedCoin
+	}
+
+	var data struct {
+		Results []struct {
+			ShortName                  string  `json:"shortName"`
+			LongName                   string  `json:"longName"`
+			Currency                   string  `json:"currency"`
+			RegularMarketPrice         float64 `json:"regularMarketPrice"`
+			RegularMarketChangePercent float64 `json:"regularMarketChangePercent"`
+			DayHigh                    float64 `json:"regularMarketDayHigh"`
+			DayLow                     float64 `json:"regularMarketDayLow"`
+			RegularMarketTime          string  `json:"regularMarketTime"`
+		} `json:"results"`
+	}
+	u := "https://brapi.dev/api/quote/" + url.PathEscape(inst.Brapi)
+	if token := config.AppConfig.BrapiToken; token != "" {
+		u += "?token=" + url.QueryEscape(token)
+	}
+	if err := getJSON(ctx, p.Name(), u, &data); err != nil {
+		return InstrumentQuote{}, err
+	}
+	if len(data.Results) == 0 || data.Results[0].RegularMarketPrice <= 0 {
+		return InstrumentQuote{}, errUnsupportedCoin
+	}
+
+	r := data.Results[0]
+	name := r.LongName
+	if name == "" {
+		name = r.ShortName
+	}
+	marketTime, _ := time.Parse(time.RFC3339, r.RegularMarketTime)
+	currency := strings.ToUpper(r.Currency)
+	if currency == "" {
+		currency = "BRL"
+	}
+
+	return InstrumentQuote{
+		Instrument: Instrument{Name: name},
+		Price:      r.RegularMarketPrice,
+		Currency:   currency,
+		Change:     r.RegularMarketChangePercent,
+		DayHigh:    r.DayHigh,
+		DayLow:     r.DayLow,
+		MarketTime: marketTime,
+	}, nil
+}
+
+//
+// ========== 🧪 Stub local =========
+//
+
+// stubProvider lê cotações fixas de QUOTE_STUB_FILE, para desenvolver e testar sem rede:
+//
+//	{"PETR4": {"name": "Petrobras PN", "price": 38.12, "currency": "BRL", "change": -1.2}}
+//
+// O arquivo é relido a cada consulta (o cache de cotações já limita a frequência).
+type stubProvider struct{}
+
+type stubQuote struct {
+	Name     string  `json:"name"`
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency"`
+	Change   float64 `json:"change"`
+	DayHigh  float64 `json:"high"`
+	DayLow   float64 `json:"low"`
+}
+
+func (stubProvider) Name() string { return "Stub" }
+
+func (stubProvider) Quote(_ context.Context, inst Instrument) (InstrumentQuote, error) {
+	data, err := os.ReadFile(config.AppConfig.QuoteStubFile)
+	if err != nil {
+		return InstrumentQuote{}, fmt.Errorf("📂 Erro ao ler %s: %w", config.AppConfig.QuoteStubFile, err)
+	}
+
+	var quotes map[string]stubQuote
+	if err := json.Unmarshal(data, &quotes); err != nil {
+		return InstrumentQuote{}, fmt.Errorf("📦 Erro ao decodificar %s: %w", config.AppConfig.QuoteStubFile, err)
+	}
+
+	q, ok := quotes[strings.ToUpper(inst.Ticker)]
+	if !ok || q.Price <= 0 {
+		return InstrumentQuote{}, errUnsupportedCoin
+	}
+	currency := strings.ToUpper(q.Currency)
+	if currency == "" {
+		currency = "BRL"
+	}
+
+	return InstrumentQuote{
+		Instrument: Instrument{Name: q.Name},
+		Price:      q.Price,
+		Currency:   currency,
+		Change:     q.Change,
+		DayHigh:    q.DayHigh,
+		DayLow:     q.DayLow,
+	}, nil
+}
+
+// unixTime converte segundos Unix em time.Time (zero quando a fonte não informa)
+func unixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
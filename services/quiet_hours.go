@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	quietHoursKey   = "quiet_hours" // ex: "22:00-07:00"
+	chatTimezoneKey = "timezone"    // ex: "America/Sao_Paulo"
+
+	// deferredCheckInterval é de quanto em quanto tempo os avisos adiados são conferidos
+	deferredCheckInterval = time.Minute
+)
+
+var (
+	// quietClock aceita 22, 22h, 22:30 e 22h30
+	quietClock = regexp.MustCompile(`^(\d{1,2})(?:[:h](\d{2})?)?$`)
+
+	// quietRangeSeparators separam início e fim (ex: 22-7, 22h às 7h, 22:00 a 07:00)
+	quietRangeSeparators = strings.NewReplacer(" às ", "-", " as ", "-", " até ", "-", " ate ", "-", " a ", "-", " ", "")
+
+	// utcOffset aceita fusos fixos como utc-3 e gmt+5:30
+	utcOffset = regexp.MustCompile(`^(?:utc|gmt)([+-]\d{1,2})(?::(\d{2}))?$`)
+
+	timezoneAliases = map[string]string{
+		"brasilia": "America/Sao_Paulo", "brasília": "America/Sao_Paulo", "brt": "America/Sao_Paulo",
+		"manaus": "America/Manaus", "lisboa": "Europe/Lisbon", "utc": "UTC", "gmt": "UTC",
+	}
+)
+
+// QuietSettings é o horário de silêncio de uma conversa: avisos não urgentes que caem nele
+// são adiados e entregues juntos, em uma única mensagem, quando o silêncio termina
+type QuietSettings struct {
+	Enabled    bool
+	Start, End int // minutos desde a meia-noite, no fuso da conversa
+	Location   *time.Location
+	DefaultTZ  bool // a conversa não escolheu fuso: vale o padrão do bot (BOT_TIMEZONE)
+}
+
+// QuietView reúne os dados do template quiet_hours
+type QuietView struct {
+	Enabled    bool
+	Start, End string
+	Timezone   string
+	DefaultTZ  bool // fuso padrão do bot, não escolhido pela conversa
+	Active     bool // a conversa está em silêncio agora
+	Pending    int  // avisos aguardando o fim do silêncio
+	Now        time.Time
+}
+
+// active informa se o instante cai no horário de silêncio (que pode virar a meia-noite)
+func (q QuietSettings) active(t time.Time) bool {
+	if !q.Enabled {
+		return false
+	}
+	local := t.In(q.Location)
+	now := local.Hour()*60 + local.Minute()
+	if q.Start < q.End {
+		return now >= q.Start && now < q.End
+	}
+	return now >= q.Start || now < q.End
+}
+
+// SendProactive envia um aviso que o bot dispara por conta própria (ATH, notícias, alertas).
+// Durante o horário de silêncio da conversa, avisos não urgentes são adiados para a mensagem
+// de resumo entregue quando o silêncio termina.
+func SendProactive(ctx context.Context, m transport.Messenger, chat types.JID, msg Text, urgent bool) {
+	if !urgent && store.DB != nil {
+		q, err := chatQuietSettings(ctx, chat.String())
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+		} else if q.active(time.Now()) {
+			mentions := make([]string, 0, len(msg.Mentions))
+			for _, jid := range msg.Mentions {
+				mentions = append(mentions, jid.String())
+			}
+			id, err := store.DeferMessage(ctx, chat.String(), msg.Body, mentions)
+			if err == nil {
+				log.Printf("🌙 Aviso #%d para %s adiado (horário de silêncio)", id, chat.String())
+				return
+			}
+			log.Printf("⚠️ %v — enviando agora", err)
+		}
+	}
+
+	if err := Send(ctx, m, chat, msg); err != nil {
+		log.Printf("❌ Falha ao enviar mensagem para %s: %v", chat.String(), err)
+	}
+}
+
+// StartDeferredDelivery confere a cada minuto as conversas com avisos adiados e, para as que
+// saíram do horário de silêncio, entrega tudo em uma única mensagem
+func StartDeferredDelivery(ctx context.Context, m transport.Messenger) {
+	if store.DB == nil {
+		return
+	}
+	if _, err := parseTimezone(config.AppConfig.Timezone); err != nil {
+		log.Printf("⚠️ BOT_TIMEZONE: %v — usando o fuso do servidor (%s)", err, time.Local)
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("🔥 Panic recuperado na entrega de avisos adiados: %v", r)
+			}
+		}()
+
+		ticker := time.NewTicker(deferredCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			deliverDeferred(ctx, m)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// QuietStatus mostra o horário de silêncio e o fuso da conversa (!silencio)
+func QuietStatus(ctx context.Context, chat string) (string, error) {
+	if store.DB == nil {
+		return "", fmt.Errorf("⚠️ Horário de silêncio indisponível: banco de dados desconectado.")
+	}
+
+	q, err := chatQuietSettings(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	pending, err := store.CountDeferred(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+
+	return RenderTemplate("quiet_hours", QuietView{
+		Enabled:   q.Enabled,
+		Start:     formatClock(q.Start),
+		End:       formatClock(q.End),
+		Timezone:  q.Location.String(),
+		DefaultTZ: q.DefaultTZ,
+		Active:    q.active(time.Now()),
+		Pending:   pending,
+		Now:       time.Now().In(q.Location),
+	})
+}
+
+// SetQuietHours define o horário de silêncio da conversa (ex: "22:00-07:00", "22h às 7h", "23-6")
+func SetQuietHours(ctx context.Context, chat, input string) error {
+	if store.DB == nil {
+		return fmt.Errorf("⚠️ Horário de silêncio indisponível: banco de dados desconectado.")
+	}
+
+	start, end, ok := parseQuietRange(input)
+	if !ok {
+		return fmt.Errorf("⚠️ Horário inválido '%s' (ex: !silencio 22:00-07:00, !silencio 23h às 7h)", input)
+	}
+	if start == end {
+		return fmt.Errorf("⚠️ O início e o fim do silêncio precisam ser diferentes")
+	}
+
+	log.Printf("🌙 Silêncio de %s: %s-%s", chat, formatClock(start), formatClock(end))
+	return store.SetChatSetting(ctx, chat, quietHoursKey, formatClock(start)+"-"+formatClock(end))
+}
+
+// DisableQuietHours desliga o silêncio; os avisos adiados saem na próxima conferência
+func DisableQuietHours(ctx context.Context, chat string) error {
+	if store.DB == nil {
+		return fmt.Errorf("⚠️ Horário de silêncio indisponível: banco de dados desconectado.")
+	}
+	log.Printf("🔔 Silêncio de %s desligado", chat)
+	return store.DeleteChatSetting(ctx, chat, quietHoursKey)
+}
+
+// SetChatTimezone define o fuso usado no horário de silêncio (ex: America/Sao_Paulo, brasilia, utc-3)
+func SetChatTimezone(ctx context.Context, chat, input string) error {
+	if store.DB == nil {
+		return fmt.Errorf("⚠️ Horário de silêncio indisponível: banco de dados desconectado.")
+	}
+
+	loc, err := parseTimezone(strings.TrimSpace(input))
+	if err != nil {
+		return err
+	}
+	log.Printf("🌍 Fuso de %s: %s", chat, loc)
+	return store.SetChatSetting(ctx, chat, chatTimezoneKey, loc.String())
+}
+
+// deliverDeferred entrega os avisos adiados das conversas que já saíram do silêncio
+func deliverDeferred(ctx context.Context, m transport.Messenger) {
+	chats, err := store.DeferredChats(ctx)
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, chat := range chats {
+		q, err := chatQuietSettings(ctx, chat)
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+			continue
+		}
+		if q.active(now) {
+			continue
+		}
+
+		jid, err := types.ParseJID(chat)
+		if err != nil {
+			log.Printf("⚠️ Avisos adiados com conversa inválida %q: %v", chat, err)
+			continue
+		}
+
+		n, err := store.DrainDeferred(ctx, chat, func(msgs []store.DeferredMessage) error {
+			return sendCatchup(ctx, m, jid, q.Location, msgs)
+		})
+		if err != nil {
+			log.Printf("⚠️ Avisos adiados de %s mantidos para a próxima tentativa: %v", chat, err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("🌅 %d aviso(s) adiado(s) entregue(s) para %s", n, chat)
+		}
+	}
+}
+
+// sendCatchup junta os avisos adiados em uma única mensagem, marcando todos os que eram marcados neles
+func sendCatchup(ctx context.Context, m transport.Messenger, jid types.JID, loc *time.Location, msgs []store.DeferredMessage) error {
+	var mentions []types.JID
+	seen := map[string]bool{}
+	for i := range msgs {
+		msgs[i].CreatedAt = msgs[i].CreatedAt.In(loc)
+		for _, s := range msgs[i].Mentions {
+			mention, err := types.ParseJID(s)
+			if err != nil || seen[s] {
+				continue
+			}
+			seen[s] = true
+			mentions = append(mentions, mention)
+		}
+	}
+
+	body, err := RenderTemplate("quiet_catchup", msgs)
+	if err != nil {
+		return err
+	}
+	return Send(ctx, m, jid, Text{Body: body, Mentions: mentions})
+}
+
+// chatQuietSettings carrega o silêncio e o fuso da conversa; sem fuso definido, vale o padrão do bot
+func chatQuietSettings(ctx context.Context, chat string) (QuietSettings, error) {
+	q := QuietSettings{Location: defaultLocation(), DefaultTZ: true}
+
+	if name, ok, err := store.GetChatSetting(ctx, chat, chatTimezoneKey); err != nil {
+		return q, err
+	} else if ok {
+		if loc, err := parseTimezone(name); err == nil {
+			q.Location, q.DefaultTZ = loc, false
+		}
+	}
+
+	value, ok, err := store.GetChatSetting(ctx, chat, quietHoursKey)
+	if err != nil || !ok {
+		return q, err
+	}
+	q.Start, q.End, q.Enabled = parseQuietRange(value)
+	return q, nil
+}
+
+// defaultLocation é o fuso de BOT_TIMEZONE; se ele for inválido, vale o do servidor (TZ)
+func defaultLocation() *time.Location {
+	loc, err := parseTimezone(config.AppConfig.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// parseQuietRange interpreta "<início>-<fim>" em minutos desde a meia-noite
+func parseQuietRange(input string) (start, end int, ok bool) {
+	from, to, found := strings.Cut(quietRangeSeparators.Replace(strings.ToLower(strings.TrimSpace(input))), "-")
+	if !found {
+		return 0, 0, false
+	}
+	if start, ok = parseClock(from); !ok {
+		return 0, 0, false
+	}
+	if end, ok = parseClock(to); !ok {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// parseClock converte 22, 22h, 22:30 ou 22h30 em minutos desde a meia-noite
+func parseClock(s string) (int, bool) {
+	m := quietClock.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	if hour > 23 || minute > 59 {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// parseTimezone aceita nomes IANA (America/Sao_Paulo), apelidos (brasilia) e fusos fixos (utc-3)
+func parseTimezone(input string) (*time.Location, error) {
+	if name, ok := timezoneAliases[strings.ToLower(input)]; ok {
+		input = name
+	}
+
+	if m := utcOffset.FindStringSubmatch(strings.ToLower(input)); m != nil {
+		hours, _ := strconv.Atoi(m[1])
+		minutes := 0
+		if m[2] != "" {
+			minutes, _ = strconv.Atoi(m[2])
+		}
+		if hours < -12 || hours > 14 || minutes > 59 {
+			return nil, fmt.Errorf("⚠️ Fuso inválido '%s'", input)
+		}
+		offset := hours*3600 + minutes*60
+		if hours < 0 {
+			offset = hours*3600 - minutes*60
+		}
+		return time.FixedZone(strings.ToUpper(input), offset), nil
+	}
+
+	loc, err := time.LoadLocation(input)
+	if err != nil || input == "" || strings.EqualFold(input, "local") {
+		return nil, fmt.Errorf("⚠️ Fuso '%s' desconhecido (ex: America/Sao_Paulo, brasilia, utc-3)", input)
+	}
+	return loc, nil
+}
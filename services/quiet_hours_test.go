@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestParseQuietRange(t *testing.T) {
+	tests := []struct {
+		in         string
+		start, end int
+		ok         bool
+	}{
+		{"22:00-07:00", 22 * 60, 7 * 60, true},
+		{"22-7", 22 * 60, 7 * 60, true},
+		{"23h às 6h", 23 * 60, 6 * 60, true},
+		{"22h30 a 06:15", 22*60 + 30, 6*60 + 15, true},
+		{"13:00 até 14:00", 13 * 60, 14 * 60, true},
+		{" 0-8 ", 0, 8 * 60, true},
+		{"22:00", 0, 0, false},
+		{"25-7", 0, 0, false},
+		{"22:60-7", 0, 0, false},
+		{"noite", 0, 0, false},
+		{"22-", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		start, end, ok := parseQuietRange(tt.in)
+		if ok != tt.ok || start != tt.start || end != tt.end {
+			t.Errorf("parseQuietRange(%q) = %d, %d, %v; quero %d, %d, %v", tt.in, start, end, ok, tt.start, tt.end, tt.ok)
+		}
+	}
+}
+
+func TestParseTimezone(t *testing.T) {
+	tests := []struct {
+		in      string
+		name    string
+		offset  int // segundos, conferido em 2024-01-15 12:00 UTC
+		wantErr bool
+	}{
+		{in: "America/Sao_Paulo", name: "America/Sao_Paulo", offset: -3 * 3600},
+		{in: "brasilia", name: "America/Sao_Paulo", offset: -3 * 3600},
+		{in: "UTC", name: "UTC"},
+		{in: "gmt", name: "UTC"},
+		{in: "utc-3", name: "UTC-3", offset: -3 * 3600},
+		{in: "gmt+5:30", name: "GMT+5:30", offset: 5*3600 + 30*60},
+		{in: "utc-3:30", name: "UTC-3:30", offset: -(3*3600 + 30*60)},
+		{in: "utc+15", wantErr: true},
+		{in: "Marte/Olimpo", wantErr: true},
+		{in: "local", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	at := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		loc, err := parseTimezone(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTimezone(%q) erro = %v, quero erro = %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if _, offset := at.In(loc).Zone(); loc.String() != tt.name || offset != tt.offset {
+			t.Errorf("parseTimezone(%q) = %s (%ds), quero %s (%ds)", tt.in, loc, offset, tt.name, tt.offset)
+		}
+	}
+}
+
+func TestQuietSettingsActive(t *testing.T) {
+	night := QuietSettings{Enabled: true, Start: 22 * 60, End: 7 * 60, Location: time.FixedZone("UTC-3", -3*3600)}
+	lunch := QuietSettings{Enabled: true, Start: 12 * 60, End: 13 * 60, Location: time.UTC}
+
+	tests := []struct {
+		name string
+		q    QuietSettings
+		at   time.Time
+		want bool
+	}{
+		{"antes da meia-noite", night, time.Date(2024, 1, 15, 1, 30, 0, 0, time.UTC), true},  // 22:30 no fuso
+		{"depois da meia-noite", night, time.Date(2024, 1, 15, 9, 59, 0, 0, time.UTC), true}, // 06:59
+		{"fim do silêncio", night, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), false},     // 07:00
+		{"durante o dia", night, time.Date(2024, 1, 15, 15, 0, 0, 0, time.UTC), false},       // 12:00
+		{"mesmo dia", lunch, time.Date(2024, 1, 15, 12, 30, 0, 0, time.UTC), true},
+		{"fora do mesmo dia", lunch, time.Date(2024, 1, 15, 13, 0, 0, 0, time.UTC), false},
+		{"desligado", QuietSettings{Start: 0, End: 23 * 60, Location: time.UTC}, time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.q.active(tt.at); got != tt.want {
+			t.Errorf("%s: active = %v, quero %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSendCatchup(t *testing.T) {
+	group := types.NewJID("120363000000000000", types.GroupServer)
+	ana := types.NewJID("5511999999999", types.DefaultUserServer)
+	bia := types.NewJID("5511888888888", types.DefaultUserServer)
+
+	msgs := []store.DeferredMessage{
+		{ID: 1, Body: "🔔 alerta da Ana", Mentions: []string{ana.String()}, CreatedAt: time.Now()},
+		{ID: 2, Body: "📰 notícias", CreatedAt: time.Now()},
+		{ID: 3, Body: "🔔 alerta da Bia e da Ana", Mentions: []string{bia.String(), ana.String()}, CreatedAt: time.Now()},
+	}
+
+	mem := transport.NewMemory()
+	if err := sendCatchup(context.Background(), mem, group, time.UTC, msgs); err != nil {
+		t.Fatalf("sendCatchup: %v", err)
+	}
+
+	sent := mem.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("%d mensagem(ns) enviada(s), quero 1", len(sent))
+	}
+	got := sent[0].Message.GetExtendedTextMessage().GetContextInfo().GetMentionedJID()
+	want := []string{ana.String(), bia.String()}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("menções = %v, quero %v", got, want)
+	}
+
+	// Sem conexão, o erro volta para que os avisos continuem guardados
+	mem.Offline = true
+	if err := sendCatchup(context.Background(), mem, group, time.UTC, msgs); err == nil {
+		t.Error("sendCatchup offline sem erro")
+	}
+}
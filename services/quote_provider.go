@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+)
+
+// QuoteProvider é uma fonte de cotações do mercado tradicional (Yahoo, brapi, stub local).
+// Instrumentos que a fonte não cobre devolvem errUnsupportedCoin.
+type QuoteProvider interface {
+	Name() string
+	Quote(ctx context.Context, inst Instrument) (InstrumentQuote, error)
+}
+
+// InstrumentQuote é a cotação de uma ação, índice, par de moedas ou commodity
+type InstrumentQuote struct {
+	Instrument
+	Price      float64
+	Currency   string  // moeda do preço (BRL, USD...); índices são cotados em pontos
+	Change     float64 // variação do dia em relação ao fechamento anterior (%)
+	DayHigh    float64
+	DayLow     float64
+	MarketTime time.Time // horário do último negócio informado pela fonte
+	Provider   string
+	FetchedAt  time.Time
+}
+
+// Age informa há quanto tempo a cotação foi obtida
+func (q InstrumentQuote) Age() time.Duration {
+	return time.Since(q.FetchedAt)
+}
+
+// quoteProviders são as fontes conhecidas, indexadas pelo nome usado em QUOTE_PROVIDERS
+var quoteProviders = map[string]QuoteProvider{
+	"yahoo": yahooProvider{},
+	"brapi": brapiProvider{},
+	"stub":  stubProvider{},
+}
+
+var (
+	instrumentCacheMu sync.RWMutex
+	instrumentCache   = map[string]InstrumentQuote{} // ticker → última cotação
+	instrumentFlight  flightGroup[InstrumentQuote]
+)
+
+// InstrumentCard reúne os dados exibidos no card de cotação do mercado tradicional (template instrument_price)
+type InstrumentCard struct {
+	Name       string
+	Ticker     string
+	Kind       InstrumentKind
+	Price      float64
+	Currency   string
+	PriceBRL   float64 // conversão para reais de ativos cotados em outra moeda (ex: ouro em USD)
+	Change     float64
+	DayHigh    float64
+	DayLow     float64
+	MarketTime time.Time
+	Provider   string
+	FetchedAt  time.Time
+}
+
+// GetPrice responde ao comando de preço (!btc, !petr4, !dolar, !ouro). Criptomoedas têm prioridade:
+// o mercado tradicional só responde pelos nomes que nenhuma moeda usa (ex: !spx é o SPX6900; o
+// índice fica em !sp500)
+func GetPrice(input string) (string, error) {
+	if inst, ok := priceInstrument(input); ok {
+		return GetInstrumentPrice(inst)
+	}
+	return GetCryptoPrice(input)
+}
+
+// priceInstrument devolve o instrumento do mercado tradicional quando o termo não é uma criptomoeda
+// (inclusive ambígua, que segue para a escolha entre as moedas)
+func priceInstrument(input string) (Instrument, bool) {
+	inst, ok := resolveInstrument(input)
+	if !ok {
+		return Instrument{}, false
+	}
+	var ambiguous *AmbiguousCoinError
+	if _, err := resolveCoin(input); err == nil || errors.As(err, &ambiguous) {
+		return Instrument{}, false
+	}
+	return inst, true
+}
+
+// GetInstrumentPrice retorna o card de cotação de um instrumento do mercado tradicional
+func GetInstrumentPrice(inst Instrument) (string, error) {
+	q, err := GetInstrumentQuote(inst)
+	if err != nil {
+		return "", err
+	}
+
+	card := InstrumentCard{
+		Name:       q.Name,
+		Ticker:     q.Ticker,
+		Kind:       q.Kind,
+		Price:      q.Price,
+		Currency:   q.Currency,
+		Change:     q.Change,
+		DayHigh:    q.DayHigh,
+		DayLow:     q.DayLow,
+		MarketTime: q.MarketTime,
+		Provider:   q.Provider,
+		FetchedAt:  q.FetchedAt,
+	}
+	if card.Name == "" {
+		card.Name = q.Ticker
+	}
+
+	// Ações e commodities cotadas lá fora ganham a conversão para reais; câmbio e índices não
+	if q.Kind != KindFX && q.Kind != KindIndex && q.Currency != "" && !strings.EqualFold(q.Currency, "BRL") {
+		if brl, err := convertToBRL(context.Background(), q.Price, q.Currency); err == nil {
+			card.PriceBRL = brl
+		} else {
+			log.Printf("⚠️ [%s] conversão para BRL indisponível: %v", q.Ticker, err)
+		}
+	}
+
+	return RenderTemplate("instrument_price", card)
+}
+
+// GetInstrumentQuote devolve a cotação do instrumento, reaproveitando o cache por PRICE_CACHE_TTL
+// (mesma política das criptomoedas: pedidos simultâneos agrupados e cotação vencida se a fonte falhar)
+func GetInstrumentQuote(inst Instrument) (InstrumentQuote, error) {
+	instrumentCacheMu.RLock()
+	cached, ok := instrumentCache[inst.Ticker]
+	instrumentCacheMu.RUnlock()
+
+	if ok && cached.Age() < config.AppConfig.PriceCacheTTL {
+		return cached, nil
+	}
+
+	quote, err := instrumentFlight.Do(inst.Ticker, func() (InstrumentQuote, error) {
+		q, err := fetchInstrumentQuote(inst)
+		if err != nil {
+			return q, err
+		}
+		instrumentCacheMu.Lock()
+		instrumentCache[inst.Ticker] = q
+		instrumentCacheMu.Unlock()
+		return q, nil
+	})
+	if err != nil {
+		if ok {
+			log.Printf("⚠️ [%s] usando cotação de %s atrás: %v", inst.Ticker, cached.Age().Round(time.Second), err)
+			return cached, nil
+		}
+		return InstrumentQuote{}, err
+	}
+	return quote, nil
+}
+
+// activeQuoteProviders devolve as fontes na ordem de prioridade de QUOTE_PROVIDERS
+func activeQuoteProviders() []QuoteProvider {
+	var list []QuoteProvider
+	for _, name := range config.AppConfig.QuoteProviders {
+		if p, ok := quoteProviders[strings.ToLower(name)]; ok {
+			list = append(list, p)
+		}
+	}
+	if len(list) == 0 {
+		list = append(list, quoteProviders["yahoo"])
+	}
+	return list
+}
+
+// fetchInstrumentQuote consulta as fontes em ordem de prioridade, com o mesmo failover e pausa
+// por limite de requisições usados nas cotações de criptomoedas
+func fetchInstrumentQuote(inst Instrument) (InstrumentQuote, error) {
+	ctx := context.Background()
+
+	var failures []string
+	unsupported := 0
+	providers := activeQuoteProviders()
+	for _, p := range providers {
+		if until, paused := providerPausedUntil(p.Name()); paused {
+			failures = append(failures, fmt.Sprintf("%s: pausada até %s", p.Name(), until.Format("15:04:05")))
+			continue
+		}
+
+		q, err := p.Quote(ctx, inst)
+		if err == nil {
+			q.Instrument = mergeInstrument(inst, q.Instrument)
+			q.Provider = p.Name()
+			q.FetchedAt = time.Now()
+			return q, nil
+		}
+
+		var rl *rateLimitError
+		if errors.As(err, &rl) {
+			pauseProvider(p.Name(), rl.retryAfter)
+		}
+		if errors.Is(err, errUnsupportedCoin) {
+			unsupported++
+		} else {
+			log.Printf("⚠️ [%s] %s falhou, tentando a próxima fonte: %v", inst.Ticker, p.Name(), err)
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", p.Name(), err))
+	}
+
+	if unsupported == len(providers) {
+		return InstrumentQuote{}, fmt.Errorf("❌ Ativo '%s' não encontrado", inst.Ticker)
+	}
+	return InstrumentQuote{}, fmt.Errorf("❌ Nenhuma fonte de cotação respondeu para '%s' (%s)", inst.Ticker, strings.Join(failures, "; "))
+}
+
+// mergeInstrument mantém o ticker e o tipo resolvidos localmente; o nome conhecido de antemão
+// tem prioridade, e o da fonte só é usado quando não há um (ex: ações da B3)
+func mergeInstrument(local, remote Instrument) Instrument {
+	if local.Name == "" {
+		local.Name = strings.TrimSpace(remote.Name)
+	}
+	return local
+}
+
+// convertToBRL converte um valor cotado em outra moeda para reais pelas taxas de câmbio
+func convertToBRL(ctx context.Context, amount float64, currency string) (float64, error) {
+	rates, err := GetFiatRates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	from := rates.PerUSD[strings.ToLower(currency)]
+	brl := rates.PerUSD["brl"]
+	if from <= 0 || brl <= 0 {
+		return 0, fmt.Errorf("moeda %s sem cotação", currency)
+	}
+	return amount / from * brl, nil
+}
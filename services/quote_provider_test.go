@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/store"
+)
+
+func TestPriceInstrumentCryptoFirst(t *testing.T) {
+	saved := coinDir
+	t.Cleanup(func() { coinDir = saved })
+
+	coinDir = newCoinDirectory()
+	coinDir.replace([]store.CoinEntry{
+		{ID: "bitcoin", Symbol: "btc", Name: "Bitcoin", Rank: 1},
+		{ID: "spx6900", Symbol: "spx", Name: "SPX6900", Rank: 80},
+		// Duas moedas sem rank com o mesmo símbolo: alias ambíguo, o usuário escolhe entre elas
+		{ID: "ouro-token", Symbol: "ouro", Name: "Ouro Token"},
+		{ID: "ouro-coin", Symbol: "ouro", Name: "Ouro Coin"},
+	}, time.Now())
+
+	tests := []struct {
+		in     string
+		ticker string // vazio = segue para a cotação de cripto
+	}{
+		{"spx", ""},
+		{"sp500", "SPX"},
+		{"btc", ""},
+		{"ouro", ""},
+		{"gold", "OURO"},
+		{"dolar", "USD/BRL"},
+		{"petr4", "PETR4"},
+		{"ibov", "IBOV"},
+	}
+
+	for _, tt := range tests {
+		inst, ok := priceInstrument(tt.in)
+		if ok != (tt.ticker != "") || inst.Ticker != tt.ticker {
+			t.Errorf("priceInstrument(%q) = %q, %v; quero %q", tt.in, inst.Ticker, ok, tt.ticker)
+		}
+	}
+}
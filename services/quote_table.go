@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/faysk/whatsapp-bot/store"
+)
+
+const (
+	maxTableCoins      = 25
+	chatDefaultCoinKey = "cotacao.moedas"
+)
+
+// defaultTableCoins é usada quando a conversa ainda não definiu sua lista padrão
+var defaultTableCoins = []string{"btc", "eth", "usdt", "xrp", "sol"}
+
+// tableSortColumns mapeia os nomes aceitos em "por <coluna>" para a ordenação
+var tableSortColumns = map[string]string{
+	"rank":     "rank",
+	"preco":    "price",
+	"preço":    "price",
+	"price":    "price",
+	"variacao": "change",
+	"variação": "change",
+	"24h":      "change",
+	"change":   "change",
+	"nome":     "name",
+	"moeda":    "name",
+	"name":     "name",
+}
+
+// QuoteTable reúne os dados do template quote_table
+type QuoteTable struct {
+	Table     string // linhas já alinhadas para o bloco monoespaçado
+	SortedBy  string
+	Unknown   []string
+	Sources   string
+	FetchedAt time.Time
+}
+
+// ParseTableArgs separa moedas e ordenação (ex: "btc eth sol por variacao")
+func ParseTableArgs(args string) (coins []string, sortBy string, err error) {
+	fields := strings.Fields(strings.ToLower(args))
+	for i := 0; i < len(fields); i++ {
+		if (fields[i] == "por" || fields[i] == "by") && i+1 < len(fields) {
+			col, ok := tableSortColumns[fields[i+1]]
+			if !ok {
+				return nil, "", fmt.Errorf("⚠️ Ordenação '%s' inválida. Use: rank, preco, variacao ou nome", fields[i+1])
+			}
+			sortBy = col
+			i++
+			continue
+		}
+		coins = append(coins, fields[i])
+	}
+	if len(coins) > maxTableCoins {
+		return nil, "", fmt.Errorf("⚠️ Máximo de %d moedas por tabela", maxTableCoins)
+	}
+	return coins, sortBy, nil
+}
+
+// RenderQuoteTable monta a tabela compacta de cotações das moedas informadas
+func RenderQuoteTable(coins []string, sortBy string) (string, error) {
+	var (
+		ids     []string
+		unknown []string
+		seen    = map[string]bool{}
+	)
+	for _, alias := range coins {
+		id, err := resolveCoin(alias)
+		var ambiguous *AmbiguousCoinError
+		if errors.As(err, &ambiguous) {
+			return "", err
+		}
+		if err != nil {
+			unknown = append(unknown, alias)
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return "", fmt.Errorf("❌ Nenhuma moeda reconhecida: %s", strings.Join(unknown, ", "))
+	}
+
+	quotes, err := GetMarketsBatch(ids)
+	if err != nil {
+		return "", err
+	}
+
+	if sortBy == "" {
+		sortBy = "rank"
+	}
+	sortQuotes(quotes, sortBy)
+
+	var (
+		providers []string
+		oldest    = quotes[0].FetchedAt
+	)
+	for _, q := range quotes {
+		if !containsString(providers, q.Provider) {
+			providers = append(providers, q.Provider)
+		}
+		if q.FetchedAt.Before(oldest) {
+			oldest = q.FetchedAt
+		}
+	}
+
+	return RenderTemplate("quote_table", QuoteTable{
+		Table:     buildQuoteTable(quotes),
+		SortedBy:  sortBy,
+		Unknown:   unknown,
+		Sources:   strings.Join(providers, ", "),
+		FetchedAt: oldest,
+	})
+}
+
+// ChatDefaultCoins devolve a lista padrão da conversa (ou a lista geral do bot)
+func ChatDefaultCoins(ctx context.Context, chat string) []string {
+	if store.DB == nil {
+		return defaultTableCoins
+	}
+	value, ok, err := store.GetChatSetting(ctx, chat, chatDefaultCoinKey)
+	if err != nil || !ok || value == "" {
+		return defaultTableCoins
+	}
+	return strings.Fields(value)
+}
+
+// SetChatDefaultCoins grava a lista padrão do !cotacao para a conversa
+func SetChatDefaultCoins(ctx context.Context, chat string, coins []string) error {
+	if store.DB == nil {
+		return fmt.Errorf("⚠️ Preferências indisponíveis: banco de dados desconectado.")
+	}
+	for _, alias := range coins {
+		if _, err := resolveCoin(alias); err != nil {
+			return err
+		}
+	}
+	return store.SetChatSetting(ctx, chat, chatDefaultCoinKey, strings.Join(coins, " "))
+}
+
+func sortQuotes(quotes []MarketQuote, by string) {
+	sort.SliceStable(quotes, func(i, j int) bool {
+		a, b := quotes[i], quotes[j]
+		switch by {
+		case "price":
+			return a.PriceUSD > b.PriceUSD
+		case "change":
+			return a.Change24h > b.Change24h
+		case "name":
+			return strings.ToLower(a.Symbol) < strings.ToLower(b.Symbol)
+		default:
+			// Moedas sem rank vão para o fim
+			if a.Rank == 0 || b.Rank == 0 {
+				return a.Rank != 0
+			}
+			return a.Rank < b.Rank
+		}
+	})
+}
+
+// buildQuoteTable alinha as colunas (# | moeda | BRL | USD | 24h) para exibição em ```mono```
+func buildQuoteTable(quotes []MarketQuote) string {
+	loc := currentLocale()
+	rows := [][]string{{"#", "", loc.Symbol("BRL"), loc.Symbol("USD"), "24h"}}
+	for _, q := range quotes {
+		rank := "-"
+		if q.Rank > 0 {
+			rank = fmt.Sprint(q.Rank)
+		}
+		change := "-"
+		if !q.NoChange24h {
+			change = loc.Percent(q.Change24h, true)
+		}
+		rows = append(rows, []string{rank, strings.ToUpper(q.Symbol), loc.Number(q.PriceBRL), loc.Number(q.PriceUSD), change})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if i == 1 {
+				cells[i] = cell + pad // símbolo alinhado à esquerda
+			} else {
+				cells[i] = pad + cell
+			}
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, " "), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func containsString(list []string, val string) bool {
+	for _, item := range list {
+		if item == val {
+			return true
+		}
+	}
+	return false
+}
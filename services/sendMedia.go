@@ -0,0 +1,311 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/faysk/whatsapp-bot/transport"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	p "google.golang.org/protobuf/proto"
+)
+
+// Outgoing é qualquer conteúdo que o bot sabe transformar em mensagem do WhatsApp.
+// Tipos com mídia fazem o upload (criptografado pelo WhatsMeow) durante o build.
+type Outgoing interface {
+	build(ctx context.Context, m transport.Messenger) (*proto.Message, string, error)
+}
+
+// Send monta o conteúdo e entrega ao transporte (com a fila de saída, quando ativa)
+func Send(ctx context.Context, m transport.Messenger, chat types.JID, out Outgoing) error {
+	msg, preview, err := out.build(ctx, m)
+	if err != nil {
+		return err
+	}
+	return m.Send(ctx, chat, msg, preview)
+}
+
+//
+// ========== 💬 Texto =========
+//
+
+// Text envia texto simples; com Mentions, os JIDs são marcados (@número) e notificados
+type Text struct {
+	Body     string
+	Mentions []types.JID
+}
+
+func (m Text) build(context.Context, transport.Messenger) (*proto.Message, string, error) {
+	if strings.TrimSpace(m.Body) == "" {
+		return nil, "", fmt.Errorf("⚠️ Conteúdo vazio — mensagem não enviada")
+	}
+	msg, preview := transport.TextMessage(m.Body, m.Mentions)
+	return msg, preview, nil
+}
+
+//
+// ========== 🖼️ Imagem =========
+//
+
+// Image envia uma imagem (PNG, JPEG ou GIF) com legenda opcional
+type Image struct {
+	Data    []byte
+	Caption string
+}
+
+func (m Image) build(ctx context.Context, tr transport.Messenger) (*proto.Message, string, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(m.Data))
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Imagem inválida: %w", err)
+	}
+
+	up, err := tr.Upload(ctx, m.Data, whatsmeow.MediaImage)
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Erro no upload da imagem: %w", err)
+	}
+
+	msg := &proto.ImageMessage{
+		Caption:       optionalString(m.Caption),
+		Mimetype:      p.String(http.DetectContentType(m.Data)),
+		Width:         p.Uint32(uint32(cfg.Width)),
+		Height:        p.Uint32(uint32(cfg.Height)),
+		JPEGThumbnail: jpegThumbnail(m.Data),
+		URL:           p.String(up.URL),
+		DirectPath:    p.String(up.DirectPath),
+		MediaKey:      up.MediaKey,
+		FileEncSHA256: up.FileEncSHA256,
+		FileSHA256:    up.FileSHA256,
+		FileLength:    p.Uint64(up.FileLength),
+	}
+	return &proto.Message{ImageMessage: msg}, previewOr(m.Caption, "🖼️ imagem"), nil
+}
+
+//
+// ========== 📄 Documento =========
+//
+
+// Document envia um arquivo qualquer (CSV, PDF, etc.); o tipo MIME é deduzido do nome
+type Document struct {
+	Data     []byte
+	FileName string
+	Caption  string
+}
+
+func (m Document) build(ctx context.Context, tr transport.Messenger) (*proto.Message, string, error) {
+	if m.FileName == "" {
+		return nil, "", fmt.Errorf("⚠️ Documento sem nome de arquivo")
+	}
+
+	mimetype := mime.TypeByExtension(filepath.Ext(m.FileName))
+	if mimetype == "" {
+		mimetype = http.DetectContentType(m.Data)
+	}
+
+	up, err := tr.Upload(ctx, m.Data, whatsmeow.MediaDocument)
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Erro no upload do documento: %w", err)
+	}
+
+	msg := &proto.DocumentMessage{
+		FileName:      p.String(m.FileName),
+		Title:         p.String(m.FileName),
+		Caption:       optionalString(m.Caption),
+		Mimetype:      p.String(mimetype),
+		URL:           p.String(up.URL),
+		DirectPath:    p.String(up.DirectPath),
+		MediaKey:      up.MediaKey,
+		FileEncSHA256: up.FileEncSHA256,
+		FileSHA256:    up.FileSHA256,
+		FileLength:    p.Uint64(up.FileLength),
+	}
+	return &proto.Message{DocumentMessage: msg}, previewOr(m.Caption, "📄 "+m.FileName), nil
+}
+
+//
+// ========== 🎧 Áudio =========
+//
+
+// Audio envia um áudio; VoiceNote=true exibe como mensagem de voz (requer OGG/Opus)
+type Audio struct {
+	Data      []byte
+	MimeType  string
+	VoiceNote bool
+}
+
+func (m Audio) build(ctx context.Context, tr transport.Messenger) (*proto.Message, string, error) {
+	mimetype := m.MimeType
+	if mimetype == "" {
+		mimetype = "audio/ogg; codecs=opus"
+	}
+
+	up, err := tr.Upload(ctx, m.Data, whatsmeow.MediaAudio)
+	if err != nil {
+		return nil, "", fmt.Errorf("❌ Erro no upload do áudio: %w", err)
+	}
+
+	msg := &proto.AudioMessage{
+		Mimetype:      p.String(mimetype),
+		PTT:           p.Bool(m.VoiceNote),
+		URL:           p.String(up.URL),
+		DirectPath:    p.String(up.DirectPath),
+		MediaKey:      up.MediaKey,
+		FileEncSHA256: up.FileEncSHA256,
+		FileSHA256:    up.FileSHA256,
+		FileLength:    p.Uint64(up.FileLength),
+	}
+	return &proto.Message{AudioMessage: msg}, "🎧 áudio", nil
+}
+
+//
+// ========== 📍 Localização =========
+//
+
+// Location envia um pin de localização
+type Location struct {
+	Latitude  float64
+	Longitude float64
+	Name      string
+	Address   string
+}
+
+func (m Location) build(context.Context, transport.Messenger) (*proto.Message, string, error) {
+	if m.Latitude < -90 || m.Latitude > 90 || m.Longitude < -180 || m.Longitude > 180 {
+		return nil, "", fmt.Errorf("⚠️ Coordenadas inválidas: %.6f, %.6f", m.Latitude, m.Longitude)
+	}
+
+	msg := &proto.LocationMessage{
+		DegreesLatitude:  p.Float64(m.Latitude),
+		DegreesLongitude: p.Float64(m.Longitude),
+		Name:             optionalString(m.Name),
+		Address:          optionalString(m.Address),
+	}
+	return &proto.Message{LocationMessage: msg}, previewOr(m.Name, "📍 localização"), nil
+}
+
+//
+// ========== 👤 Contato =========
+//
+
+// Contact envia um cartão de contato (vCard) com nome e telefone no formato internacional
+type Contact struct {
+	Name  string
+	Phone string
+}
+
+func (m Contact) build(context.Context, transport.Messenger) (*proto.Message, string, error) {
+	digits := strings.TrimPrefix(strings.TrimSpace(m.Phone), "+")
+	if m.Name == "" || digits == "" {
+		return nil, "", fmt.Errorf("⚠️ Contato precisa de nome e telefone")
+	}
+
+	msg := &proto.ContactMessage{
+		DisplayName: p.String(m.Name),
+		Vcard:       p.String(BuildVCard(m.Name, digits)),
+	}
+	return &proto.Message{ContactMessage: msg}, "👤 " + m.Name, nil
+}
+
+// BuildVCard gera um vCard 3.0 com o waid, para o WhatsApp abrir a conversa direto
+func BuildVCard(name, phone string) string {
+	return fmt.Sprintf("BEGIN:VCARD\nVERSION:3.0\nFN:%s\nTEL;type=CELL;type=VOICE;waid=%s:+%s\nEND:VCARD", name, phone, phone)
+}
+
+//
+// ========== 🔗 Link com prévia =========
+//
+
+// LinkPreview envia texto com prévia de link (título, descrição e miniatura opcionais)
+type LinkPreview struct {
+	Text        string
+	URL         string
+	Title       string
+	Description string
+	Thumbnail   []byte
+}
+
+func (m LinkPreview) build(context.Context, transport.Messenger) (*proto.Message, string, error) {
+	if m.URL == "" {
+		return nil, "", fmt.Errorf("⚠️ Link ausente")
+	}
+
+	text := m.Text
+	if !strings.Contains(text, m.URL) {
+		text = strings.TrimSpace(text + "\n" + m.URL)
+	}
+
+	msg := &proto.ExtendedTextMessage{
+		Text:          p.String(text),
+		MatchedText:   p.String(m.URL),
+		Title:         optionalString(m.Title),
+		Description:   optionalString(m.Description),
+		JPEGThumbnail: m.Thumbnail,
+		PreviewType:   proto.ExtendedTextMessage_NONE.Enum(),
+	}
+	return &proto.Message{ExtendedTextMessage: msg}, text, nil
+}
+
+//
+// ========== 🧰 Utilitários =========
+//
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return p.String(s)
+}
+
+func previewOr(text, fallback string) string {
+	if text != "" {
+		return text
+	}
+	return fallback
+}
+
+// jpegThumbnail gera uma miniatura JPEG (até 72px) para a prévia da imagem no chat
+func jpegThumbnail(data []byte) []byte {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+
+	const maxSide = 72
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return nil
+	}
+	tw, th := maxSide, h*maxSide/w
+	if h > w {
+		tw, th = w*maxSide/h, maxSide
+	}
+	if tw < 1 {
+		tw = 1
+	}
+	if th < 1 {
+		th = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		for x := 0; x < tw; x++ {
+			dst.Set(x, y, src.At(b.Min.X+x*w/tw, b.Min.Y+y*h/th))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 60}); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
@@ -4,45 +4,36 @@ import (
 	"context"
 	"log"
 
-	"go.mau.fi/whatsmeow"
-	"go.mau.fi/whatsmeow/binary/proto"
+	"github.com/faysk/whatsapp-bot/transport"
 	"go.mau.fi/whatsmeow/types"
-	p "google.golang.org/protobuf/proto"
 )
 
 // SendReply envia uma mensagem para um JID (grupo ou contato)
-func SendReply(ctx context.Context, client *whatsmeow.Client, chat types.JID, content string) {
+func SendReply(ctx context.Context, m transport.Messenger, chat types.JID, content string) {
 	if content == "" {
 		log.Println("⚠️ Conteúdo vazio — mensagem não enviada.")
 		return
 	}
 
-	msg := &proto.Message{
-		Conversation: p.String(content),
+	msg, preview := transport.TextMessage(content, nil)
+	if err := m.Send(ctx, chat, msg, preview); err != nil {
+		log.Printf("❌ Falha ao enviar mensagem para %s: %v", chat.String(), err)
 	}
+}
 
-	if _, err := client.SendMessage(ctx, chat, msg, whatsmeow.SendRequestExtra{}); err != nil {
+// SendReplyMentions envia texto marcando os JIDs informados (notificação de @menção no grupo)
+func SendReplyMentions(ctx context.Context, m transport.Messenger, chat types.JID, content string, mentions []types.JID) {
+	if err := Send(ctx, m, chat, Text{Body: content, Mentions: mentions}); err != nil {
 		log.Printf("❌ Falha ao enviar mensagem para %s: %v", chat.String(), err)
-	} else {
-		log.Printf("📤 Mensagem enviada para %s", chat.String())
 	}
 }
 
 // SendToNumber envia mensagem diretamente para um número com formato internacional (ex: 5511987654321)
-func SendToNumber(ctx context.Context, client *whatsmeow.Client, phone string, content string) {
+func SendToNumber(ctx context.Context, m transport.Messenger, phone string, content string) {
 	if phone == "" || content == "" {
 		log.Println("⚠️ Número ou conteúdo vazio — mensagem não enviada.")
 		return
 	}
 
-	jid := types.NewJID(phone, "s.whatsapp.net")
-	msg := &proto.Message{
-		Conversation: p.String(content),
-	}
-
-	if _, err := client.SendMessage(ctx, jid, msg, whatsmeow.SendRequestExtra{}); err != nil {
-		log.Printf("❌ Erro ao enviar mensagem para %s: %v", phone, err)
-	} else {
-		log.Printf("📬 Mensagem enviada para %s", phone)
-	}
+	SendReply(ctx, m, types.NewJID(phone, types.DefaultUserServer), content)
 }
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/store"
+	"github.com/faysk/whatsapp-bot/transport"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	// EventATH é o aviso de nova máxima histórica das moedas monitoradas
+	EventATH = "ath"
+
+	// subscriptionsSeededKey marca a inscrição inicial dos números autorizados (antes, todos recebiam tudo)
+	subscriptionsSeededKey = "subscriptions_seeded"
+
+	subscriptionUsage = "⚠️ Uso: !assinar ath [moedas] — sem moedas, assina todas as monitoradas (ex: !assinar ath btc eth)"
+)
+
+// subscriptionEvents são os nomes aceitos para cada tipo de aviso
+var subscriptionEvents = map[string]string{
+	"ath": EventATH, "recorde": EventATH, "recordes": EventATH, "maxima": EventATH, "máxima": EventATH,
+}
+
+// SubscriptionView é uma linha do template subscriptions: o evento e as moedas assinadas
+type SubscriptionView struct {
+	Event   string
+	All     bool     // assina todas as moedas monitoradas
+	Symbols []string // moedas assinadas individualmente (quando All=false)
+}
+
+// Subscribe inscreve a conversa em um tipo de aviso (ex: !assinar ath btc eth)
+func Subscribe(ctx context.Context, chat, args string) (string, error) {
+	if store.DB == nil {
+		return "", fmt.Errorf("⚠️ Assinaturas indisponíveis: banco de dados desconectado.")
+	}
+	event, ids, err := parseSubscription(args)
+	if err != nil {
+		return "", err
+	}
+
+	if len(ids) == 0 {
+		if err := store.Subscribe(ctx, chat, event, []string{store.AllCoins}); err != nil {
+			return "", err
+		}
+		log.Printf("🔔 %s assinou %s (todas as moedas)", chat, event)
+		return fmt.Sprintf("✅ Assinatura de %s ativada para todas as moedas monitoradas.", strings.ToUpper(event)), nil
+	}
+
+	if err := store.Subscribe(ctx, chat, event, ids); err != nil {
+		return "", err
+	}
+	log.Printf("🔔 %s assinou %s: %s", chat, event, strings.Join(ids, ", "))
+
+	msg := fmt.Sprintf("✅ Assinatura de %s ativada: %s.", strings.ToUpper(event), strings.Join(coinSymbols(ids), ", "))
+	if missing := notMonitored(ids); len(missing) > 0 {
+		msg += fmt.Sprintf("\n⚠️ %s fora do monitor — peça a um admin: !monitor adicionar %s",
+			strings.Join(coinSymbols(missing), ", "), strings.ToLower(strings.Join(coinSymbols(missing), " ")))
+	}
+	return msg, nil
+}
+
+// Unsubscribe cancela a assinatura da conversa; sem moedas, cancela todo o evento (ex: !cancelar ath btc)
+func Unsubscribe(ctx context.Context, chat, args string) (string, error) {
+	if store.DB == nil {
+		return "", fmt.Errorf("⚠️ Assinaturas indisponíveis: banco de dados desconectado.")
+	}
+	event, ids, err := parseSubscription(args)
+	if err != nil {
+		return "", err
+	}
+
+	n, err := store.Unsubscribe(ctx, chat, event, ids)
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 {
+		if n == 0 {
+			return fmt.Sprintf("ℹ️ Esta conversa não assina %s.", strings.ToUpper(event)), nil
+		}
+		log.Printf("🔕 %s cancelou %s", chat, event)
+		return fmt.Sprintf("🔕 Assinatura de %s cancelada.", strings.ToUpper(event)), nil
+	}
+
+	symbols := strings.Join(coinSymbols(ids), ", ")
+	msg := fmt.Sprintf("🔕 %s de %s cancelado.", strings.ToUpper(event), symbols)
+	if n == 0 {
+		msg = fmt.Sprintf("ℹ️ Esta conversa não assina %s de %s.", strings.ToUpper(event), symbols)
+	} else {
+		log.Printf("🔕 %s cancelou %s: %s", chat, event, strings.Join(ids, ", "))
+	}
+
+	// Quem assina "todas" continua recebendo as moedas canceladas individualmente
+	subs, err := store.ListSubscriptions(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+	for _, s := range subs {
+		if s.Event == event && s.Coin == store.AllCoins {
+			msg += fmt.Sprintf("\n⚠️ A conversa ainda assina %s de todas as moedas — use !cancelar %s e assine só as desejadas.", strings.ToUpper(event), event)
+			break
+		}
+	}
+	return msg, nil
+}
+
+// ListSubscriptions mostra as assinaturas da conversa (!assinaturas)
+func ListSubscriptions(ctx context.Context, chat string) (string, error) {
+	if store.DB == nil {
+		return "", fmt.Errorf("⚠️ Assinaturas indisponíveis: banco de dados desconectado.")
+	}
+
+	subs, err := store.ListSubscriptions(ctx, chat)
+	if err != nil {
+		return "", err
+	}
+
+	var views []SubscriptionView
+	index := map[string]int{}
+	for _, s := range subs {
+		i, ok := index[s.Event]
+		if !ok {
+			i = len(views)
+			index[s.Event] = i
+			views = append(views, SubscriptionView{Event: s.Event})
+		}
+		if s.Coin == store.AllCoins {
+			views[i].All = true
+			continue
+		}
+		views[i].Symbols = append(views[i].Symbols, strings.ToUpper(coinDir.coin(s.Coin).Symbol))
+	}
+	return RenderTemplate("subscriptions", views)
+}
+
+// notifySubscribers envia o aviso do evento às conversas que assinam a moeda. Sem banco, mantém
+// o comportamento antigo: todos os números autorizados recebem.
+func notifySubscribers(ctx context.Context, m transport.Messenger, event, coinID, msg string) {
+	if store.DB == nil {
+		for _, number := range config.AppConfig.AuthorizedNumbers {
+			SendToNumber(ctx, m, number, msg)
+		}
+		return
+	}
+
+	chats, err := store.Subscribers(ctx, event, coinID)
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		return
+	}
+	for _, c := range chats {
+		jid, err := types.ParseJID(c)
+		if err != nil {
+			log.Printf("⚠️ Assinatura com conversa inválida %q: %v", c, err)
+			continue
+		}
+		SendProactive(ctx, m, jid, Text{Body: msg}, false)
+	}
+	log.Printf("📨 [%s] %s enviado a %d conversa(s)", coinID, event, len(chats))
+}
+
+// seedSubscriptions inscreve, uma única vez, os números autorizados em todos os avisos de ATH,
+// que era o que eles recebiam antes das assinaturas por conversa
+func seedSubscriptions(ctx context.Context) {
+	if store.DB == nil {
+		return
+	}
+
+	var chats []string
+	for _, number := range config.AppConfig.AuthorizedNumbers {
+		chats = append(chats, types.NewJID(number, types.DefaultUserServer).String())
+	}
+	seeded, err := store.SeedSubscriptions(ctx, chats, EventATH, subscriptionsSeededKey)
+	if err != nil {
+		log.Printf("⚠️ %v", err)
+		return
+	}
+	if seeded {
+		log.Printf("🔔 %d número(s) autorizado(s) inscrito(s) nos avisos de ATH", len(chats))
+	}
+}
+
+// parseSubscription interpreta "<evento> [moedas...]" e resolve as moedas para IDs
+func parseSubscription(args string) (event string, ids []string, err error) {
+	fields := strings.Fields(strings.ToLower(args))
+	if len(fields) == 0 {
+		return "", nil, fmt.Errorf("%s", subscriptionUsage)
+	}
+	event, ok := subscriptionEvents[fields[0]]
+	if !ok {
+		return "", nil, fmt.Errorf("⚠️ Evento '%s' desconhecido — disponível: ath", fields[0])
+	}
+
+	for _, alias := range fields[1:] {
+		if alias == "todas" || alias == "tudo" || alias == "all" {
+			return event, nil, nil
+		}
+		id, err := resolveCoin(alias)
+		if err != nil {
+			return "", nil, err
+		}
+		if !containsString(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return event, ids, nil
+}
+
+// notMonitored devolve as moedas que o monitor não verifica (e que, portanto, nunca vão avisar)
+func notMonitored(ids []string) []string {
+	var monitored []string
+	for _, alias := range MonitoredCoins() {
+		if id, err := resolveCoin(alias); err == nil {
+			monitored = append(monitored, id)
+		}
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if !containsString(monitored, id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+func coinSymbols(ids []string) []string {
+	symbols := make([]string, 0, len(ids))
+	for _, id := range ids {
+		symbols = append(symbols, strings.ToUpper(coinDir.coin(id).Symbol))
+	}
+	return symbols
+}
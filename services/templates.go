@@ -0,0 +1,214 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/faysk/whatsapp-bot/config"
+	"github.com/faysk/whatsapp-bot/format"
+)
+
+// defaultLanguage é usado quando o idioma configurado não tem a variante pedida
+const defaultLanguage = "pt-BR"
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+var (
+	templatesMu sync.RWMutex
+	templateSet map[string]*template.Template // idioma → conjunto de templates
+)
+
+// templateFuncs são os helpers disponíveis em todos os templates de mensagem
+var templateFuncs = template.FuncMap{
+	"bold":   func(s any) string { return "*" + fmt.Sprint(s) + "*" },
+	"italic": func(s any) string { return "_" + fmt.Sprint(s) + "_" },
+	"strike": func(s any) string { return "~" + fmt.Sprint(s) + "~" },
+	"mono":   func(s any) string { return "```" + fmt.Sprint(s) + "```" },
+	"upper":  func(s string) string { return strings.ToUpper(s) },
+	"trim":   strings.TrimSpace,
+	"join":   strings.Join,
+	"inc":    func(i int) int { return i + 1 },
+	"numBR":  format.PtBR.Number,
+	"numUS":  format.EnUS.Number,
+	"date":   func(t time.Time) string { return t.Format("02/01/2006 15:04") },
+	"age":    formatAge,
+}
+
+// localeFuncs são os helpers numéricos que seguem o idioma do template (separadores e símbolos)
+func localeFuncs(l format.Locale) template.FuncMap {
+	return template.FuncMap{
+		"num":   l.Number,
+		"money": func(v float64, currency string) string { return l.Money(v, currency) },
+		"brl":   func(v float64) string { return l.Money(v, "BRL") },
+		"usd":   func(v float64) string { return l.Money(v, "USD") },
+		"pct":   func(v float64) string { return l.Percent(v, false) },
+		// compact abrevia valores grandes: {{compact .MarketCapBRL "BRL"}} → R$ 1,2 tri
+		"compact": func(v float64, currency ...string) string {
+			if len(currency) > 0 {
+				return l.CompactMoney(v, currency[0])
+			}
+			return l.CompactNumber(v)
+		},
+		// variation formata variação percentual com indicador colorido
+		"variation": func(v float64) string {
+			switch pct := l.Percent(v, false); {
+			case v > 0:
+				return "🟢 " + pct
+			case v < 0:
+				return "🔴 " + pct
+			default:
+				return "⚪ " + pct
+			}
+		},
+	}
+}
+
+// currentLocale é o formato numérico do idioma configurado (BOT_LANG)
+func currentLocale() format.Locale {
+	return format.ForLanguage(languageCandidates(config.AppConfig.Language)[0])
+}
+
+// LoadTemplates carrega os templates embutidos e aplica por cima os arquivos de TEMPLATES_DIR.
+// Estrutura esperada: <dir>/<idioma>/<nome>.tmpl (ex: templates/pt-BR/crypto_price.tmpl)
+func LoadTemplates() error {
+	embedded, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao abrir templates embutidos: %w", err)
+	}
+
+	set, err := parseTemplateTree(embedded, nil)
+	if err != nil {
+		return err
+	}
+
+	overrides := 0
+	if dir := config.AppConfig.TemplatesDir; dir != "" {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			if overrides, err = parseTemplateDir(set, dir); err != nil {
+				return err
+			}
+		}
+	}
+
+	templatesMu.Lock()
+	templateSet = set
+	templatesMu.Unlock()
+
+	log.Printf("📝 Templates carregados: %d idioma(s), %d arquivo(s) de %s.", len(set), overrides, config.AppConfig.TemplatesDir)
+	return nil
+}
+
+// RenderTemplate renderiza o template no idioma configurado (BOT_LANG), com fallback para pt-BR
+func RenderTemplate(name string, data any) (string, error) {
+	return RenderTemplateLang(config.AppConfig.Language, name, data)
+}
+
+// RenderTemplateLang renderiza o template em um idioma específico
+func RenderTemplateLang(lang, name string, data any) (string, error) {
+	templatesMu.RLock()
+	set := templateSet
+	templatesMu.RUnlock()
+
+	if set == nil {
+		if err := LoadTemplates(); err != nil {
+			return "", err
+		}
+		templatesMu.RLock()
+		set = templateSet
+		templatesMu.RUnlock()
+	}
+
+	for _, candidate := range languageCandidates(lang) {
+		tmpl, ok := set[candidate]
+		if !ok || tmpl.Lookup(name) == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			return "", fmt.Errorf("❌ Erro ao renderizar template '%s' (%s): %w", name, candidate, err)
+		}
+		return strings.TrimSpace(buf.String()), nil
+	}
+
+	return "", fmt.Errorf("❌ Template '%s' não encontrado", name)
+}
+
+//
+// ========== 🧰 Utilitários =========
+//
+
+// parseTemplateTree lê <idioma>/<nome>.tmpl de um fs.FS, somando ao conjunto existente
+func parseTemplateTree(fsys fs.FS, set map[string]*template.Template) (map[string]*template.Template, error) {
+	if set == nil {
+		set = map[string]*template.Template{}
+	}
+
+	files, err := fs.Glob(fsys, "*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		lang := path.Dir(file)
+		name := strings.TrimSuffix(path.Base(file), ".tmpl")
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("❌ Erro ao ler template %s: %w", file, err)
+		}
+		if err := addTemplate(set, lang, name, string(content)); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// parseTemplateDir sobrescreve/acrescenta templates a partir de um diretório do disco
+func parseTemplateDir(set map[string]*template.Template, dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.tmpl"))
+	if err != nil || len(files) == 0 {
+		return 0, err
+	}
+
+	if _, err := parseTemplateTree(os.DirFS(dir), set); err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+func addTemplate(set map[string]*template.Template, lang, name, content string) error {
+	root, ok := set[lang]
+	if !ok {
+		root = template.New(lang).Funcs(templateFuncs).Funcs(localeFuncs(format.ForLanguage(lang)))
+		set[lang] = root
+	}
+	if _, err := root.New(name).Parse(content); err != nil {
+		return fmt.Errorf("❌ Template inválido %s/%s: %w", lang, name, err)
+	}
+	return nil
+}
+
+// languageCandidates normaliza BOT_LANG (ex: en_US.UTF-8 → en-US, en) e termina no idioma padrão
+func languageCandidates(lang string) []string {
+	lang = strings.ReplaceAll(strings.SplitN(lang, ".", 2)[0], "_", "-")
+
+	var list []string
+	if lang != "" {
+		list = append(list, lang)
+		if base, _, found := strings.Cut(lang, "-"); found {
+			list = append(list, base)
+		}
+	}
+	return append(list, defaultLanguage)
+}
@@ -33,6 +33,12 @@ func InitWhatsAppClient(ctx context.Context) (*whatsmeow.Client, error) {
 		return nil, fmt.Errorf("❌ Erro ao conectar ao PostgreSQL: %w", err)
 	}
 
+	// 🗄️ Compartilha a conexão com as tabelas próprias do bot (fila de saída etc.)
+	store.DB = db
+	if err := store.Migrate(ctx); err != nil {
+		return nil, err
+	}
+
 	// 📦 Inicializa container de sessão
 	container := sqlstore.NewWithDB(db, "postgres", logger)
 
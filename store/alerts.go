@@ -0,0 +1,174 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const alertsSchema = `
+CREATE TABLE IF NOT EXISTS bot_price_alerts (
+  id            BIGSERIAL PRIMARY KEY,
+  chat          TEXT NOT NULL,
+  created_by    TEXT NOT NULL,
+  coin_id       TEXT NOT NULL,
+  symbol        TEXT NOT NULL,
+  direction     TEXT NOT NULL CHECK (direction IN ('above', 'below')),
+  target        DOUBLE PRECISION NOT NULL,
+  currency      TEXT NOT NULL,
+  recurring     BOOLEAN NOT NULL DEFAULT false,
+  armed         BOOLEAN NOT NULL DEFAULT true,
+  last_fired_at TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS bot_price_alerts_chat_idx ON bot_price_alerts (chat);
+ALTER TABLE bot_price_alerts ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'price';
+ALTER TABLE bot_price_alerts ADD COLUMN IF NOT EXISTS window_secs INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bot_price_alerts ADD COLUMN IF NOT EXISTS cooldown_secs INTEGER NOT NULL DEFAULT 0;
+-- Bancos criados antes dos alertas de variação só aceitam above/below: troca o CHECK uma única vez
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM pg_constraint
+    WHERE conrelid = 'bot_price_alerts'::regclass
+      AND conname = 'bot_price_alerts_direction_check'
+      AND pg_get_constraintdef(oid) LIKE '%both%'
+  ) THEN
+    ALTER TABLE bot_price_alerts DROP CONSTRAINT IF EXISTS bot_price_alerts_direction_check;
+    ALTER TABLE bot_price_alerts ADD CONSTRAINT bot_price_alerts_direction_check CHECK (direction IN ('above', 'below', 'both'));
+  END IF;
+END $$;
+ALTER TABLE bot_price_alerts ADD COLUMN IF NOT EXISTS urgent BOOLEAN NOT NULL DEFAULT false;
+`
+
+// Tipos de alerta
+const (
+	AlertPrice = "price" // preço acima/abaixo de um valor
+	AlertMove  = "move"  // variação percentual dentro de uma janela de tempo
+)
+
+// PriceAlert é um alerta criado por um usuário (ex: !alerta btc > 600000 brl, !alerta eth ±5% 1h).
+// Alertas de preço recorrentes ficam desarmados depois de disparar e só voltam a valer quando o preço
+// retorna para o outro lado do alvo; os demais são apagados ao disparar. Alertas de variação são
+// sempre recorrentes e respeitam o intervalo mínimo (Cooldown) entre dois avisos. Alertas urgentes
+// são avisados mesmo durante o horário de silêncio da conversa.
+type PriceAlert struct {
+	ID          int64
+	Chat        string
+	CreatedBy   string
+	Kind        string // price | move
+	CoinID      string
+	Symbol      string
+	Direction   string  // above | below | both (alta, queda ou ambas, nos alertas de variação)
+	Target      float64 // preço alvo ou variação mínima em %
+	Currency    string  // brl | usd
+	Window      time.Duration
+	Cooldown    time.Duration
+	Recurring   bool
+	Urgent      bool
+	Armed       bool
+	LastFiredAt *time.Time
+	CreatedAt   time.Time
+}
+
+const alertColumns = `id, chat, created_by, kind, coin_id, symbol, direction, target, currency, window_secs, cooldown_secs, recurring, urgent, armed, last_fired_at, created_at`
+
+// CreatePriceAlert grava o alerta e devolve o ID gerado
+func CreatePriceAlert(ctx context.Context, a PriceAlert) (int64, error) {
+	var id int64
+	err := DB.QueryRowContext(ctx, `
+		INSERT INTO bot_price_alerts (chat, created_by, kind, coin_id, symbol, direction, target, currency, window_secs, cooldown_secs, recurring, urgent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`,
+		a.Chat, a.CreatedBy, a.Kind, a.CoinID, a.Symbol, a.Direction, a.Target, a.Currency,
+		int(a.Window.Seconds()), int(a.Cooldown.Seconds()), a.Recurring, a.Urgent,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Erro ao criar alerta: %w", err)
+	}
+	return id, nil
+}
+
+// ListPriceAlerts retorna os alertas da conversa, do mais antigo para o mais novo
+func ListPriceAlerts(ctx context.Context, chat string) ([]PriceAlert, error) {
+	return queryPriceAlerts(ctx, `SELECT `+alertColumns+` FROM bot_price_alerts WHERE chat = $1 ORDER BY id`, chat)
+}
+
+// AllPriceAlerts retorna todos os alertas, para a avaliação do monitor
+func AllPriceAlerts(ctx context.Context) ([]PriceAlert, error) {
+	return queryPriceAlerts(ctx, `SELECT `+alertColumns+` FROM bot_price_alerts ORDER BY id`)
+}
+
+// CountPriceAlerts informa quantos alertas a conversa já tem
+func CountPriceAlerts(ctx context.Context, chat string) (int, error) {
+	var n int
+	if err := DB.QueryRowContext(ctx, `SELECT count(*) FROM bot_price_alerts WHERE chat = $1`, chat).Scan(&n); err != nil {
+		return 0, fmt.Errorf("❌ Erro ao contar alertas: %w", err)
+	}
+	return n, nil
+}
+
+// DeletePriceAlert remove um alerta da conversa; alertas de outras conversas não são afetados
+func DeletePriceAlert(ctx context.Context, chat string, id int64) error {
+	res, err := DB.ExecContext(ctx, `DELETE FROM bot_price_alerts WHERE id = $1 AND chat = $2`, id, chat)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao remover alerta: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("⚠️ Alerta #%d não encontrado nesta conversa", id)
+	}
+	return nil
+}
+
+// FirePriceAlert registra o disparo: alertas únicos são apagados, recorrentes ficam desarmados
+// e os de variação apenas guardam o horário (base do cooldown)
+func FirePriceAlert(ctx context.Context, a PriceAlert) error {
+	var err error
+	switch {
+	case a.Kind == AlertMove:
+		_, err = DB.ExecContext(ctx, `UPDATE bot_price_alerts SET last_fired_at = now() WHERE id = $1`, a.ID)
+	case a.Recurring:
+		_, err = DB.ExecContext(ctx,
+			`UPDATE bot_price_alerts SET armed = false, last_fired_at = now() WHERE id = $1`, a.ID)
+	default:
+		_, err = DB.ExecContext(ctx, `DELETE FROM bot_price_alerts WHERE id = $1`, a.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao registrar disparo do alerta #%d: %w", a.ID, err)
+	}
+	return nil
+}
+
+// RearmPriceAlert volta a armar um alerta recorrente (o preço voltou para o outro lado do alvo)
+func RearmPriceAlert(ctx context.Context, id int64) error {
+	if _, err := DB.ExecContext(ctx, `UPDATE bot_price_alerts SET armed = true WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("❌ Erro ao rearmar alerta #%d: %w", id, err)
+	}
+	return nil
+}
+
+func queryPriceAlerts(ctx context.Context, query string, args ...any) ([]PriceAlert, error) {
+	rows, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar alertas: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []PriceAlert
+	for rows.Next() {
+		var a PriceAlert
+		var fired sql.NullTime
+		var window, cooldown int
+		if err := rows.Scan(&a.ID, &a.Chat, &a.CreatedBy, &a.Kind, &a.CoinID, &a.Symbol, &a.Direction,
+			&a.Target, &a.Currency, &window, &cooldown, &a.Recurring, &a.Urgent, &a.Armed, &fired, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("❌ Erro ao ler alerta: %w", err)
+		}
+		a.Window = time.Duration(window) * time.Second
+		a.Cooldown = time.Duration(cooldown) * time.Second
+		if fired.Valid {
+			a.LastFiredAt = &fired.Time
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
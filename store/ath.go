@@ -0,0 +1,163 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const athSchema = `
+CREATE TABLE IF NOT EXISTS bot_ath_records (
+  symbol         TEXT PRIMARY KEY,
+  coin_id        TEXT NOT NULL DEFAULT '',
+  ath_usd        DOUBLE PRECISION NOT NULL DEFAULT 0,
+  ath_at         TIMESTAMPTZ,
+  last_price_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+  last_seen_at   TIMESTAMPTZ,
+  alert_sent_at  TIMESTAMPTZ
+);
+`
+
+// ATHRecord é o estado do monitor para uma moeda: o maior preço que já gerou aviso,
+// o último preço visto e quando o último aviso foi enviado
+type ATHRecord struct {
+	Symbol       string
+	CoinID       string
+	ATHUSD       float64
+	ATHAt        *time.Time
+	LastPriceUSD float64
+	LastSeenAt   *time.Time
+	AlertSentAt  *time.Time
+}
+
+// ATHSeen é um preço observado pelo monitor
+type ATHSeen struct {
+	Symbol   string
+	CoinID   string
+	PriceUSD float64
+	At       time.Time
+}
+
+// LoadATHRecords retorna o estado do monitor de todas as moedas, indexado pelo símbolo (BTC, ETH...)
+func LoadATHRecords(ctx context.Context) (map[string]ATHRecord, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT symbol, coin_id, ath_usd, ath_at, last_price_usd, last_seen_at, alert_sent_at
+		FROM bot_ath_records`)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao carregar recordes de ATH: %w", err)
+	}
+	defer rows.Close()
+
+	records := map[string]ATHRecord{}
+	for rows.Next() {
+		var r ATHRecord
+		var athAt, seenAt, sentAt sql.NullTime
+		if err := rows.Scan(&r.Symbol, &r.CoinID, &r.ATHUSD, &athAt, &r.LastPriceUSD, &seenAt, &sentAt); err != nil {
+			return nil, fmt.Errorf("❌ Erro ao ler recorde de ATH: %w", err)
+		}
+		r.ATHAt, r.LastSeenAt, r.AlertSentAt = nullTime(athAt), nullTime(seenAt), nullTime(sentAt)
+		records[r.Symbol] = r
+	}
+	return records, rows.Err()
+}
+
+// RecordATHSeen grava o último preço visto de cada moeda, em uma única transação
+func RecordATHSeen(ctx context.Context, seen []ATHSeen) error {
+	if len(seen) == 0 {
+		return nil
+	}
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO bot_ath_records (symbol, coin_id, last_price_usd, last_seen_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (symbol) DO UPDATE SET
+		  coin_id = EXCLUDED.coin_id, last_price_usd = EXCLUDED.last_price_usd, last_seen_at = EXCLUDED.last_seen_at`)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao preparar atualização de preços: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, s := range seen {
+		if _, err := stmt.ExecContext(ctx, s.Symbol, s.CoinID, s.PriceUSD, s.At); err != nil {
+			return fmt.Errorf("❌ Erro ao gravar preço de %s: %w", s.Symbol, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ClaimATH registra um novo recorde se o preço superar o último que gerou aviso.
+// A linha fica bloqueada durante a transação, então duas verificações simultâneas não
+// avisam o mesmo recorde duas vezes; claimed=true indica que o aviso deve ser enviado.
+func ClaimATH(ctx context.Context, symbol, coinID string, priceUSD float64) (claimed bool, err error) {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("❌ Erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO bot_ath_records (symbol, coin_id) VALUES ($1, $2) ON CONFLICT (symbol) DO NOTHING`,
+		symbol, coinID,
+	); err != nil {
+		return false, fmt.Errorf("❌ Erro ao preparar recorde de %s: %w", symbol, err)
+	}
+
+	var current float64
+	err = tx.QueryRowContext(ctx,
+		`SELECT ath_usd FROM bot_ath_records WHERE symbol = $1 FOR UPDATE`, symbol,
+	).Scan(&current)
+	if err != nil {
+		return false, fmt.Errorf("❌ Erro ao consultar recorde de %s: %w", symbol, err)
+	}
+	if priceUSD <= current {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE bot_ath_records SET ath_usd = $2, ath_at = now(), alert_sent_at = now(), coin_id = $3
+		WHERE symbol = $1`,
+		symbol, priceUSD, coinID,
+	); err != nil {
+		return false, fmt.Errorf("❌ Erro ao gravar recorde de %s: %w", symbol, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("❌ Erro ao confirmar recorde de %s: %w", symbol, err)
+	}
+	return true, nil
+}
+
+// ImportATHRecords grava recordes vindos do antigo crypto_records.json, sem nunca reduzir um
+// recorde já existente, e marca a importação como feita (marker) na mesma transação
+func ImportATHRecords(ctx context.Context, records map[string]ATHRecord, marker string) error {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	for symbol, r := range records {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO bot_ath_records (symbol, ath_usd, ath_at) VALUES ($1, $2, $3)
+			ON CONFLICT (symbol) DO UPDATE SET
+			  ath_usd = GREATEST(bot_ath_records.ath_usd, EXCLUDED.ath_usd),
+			  ath_at  = CASE WHEN EXCLUDED.ath_usd > bot_ath_records.ath_usd THEN EXCLUDED.ath_at ELSE bot_ath_records.ath_at END`,
+			symbol, r.ATHUSD, r.ATHAt,
+		); err != nil {
+			return fmt.Errorf("❌ Erro ao importar recorde de %s: %w", symbol, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO bot_chat_settings (chat, key, value) VALUES ($1, $2, now()::text)
+		ON CONFLICT (chat, key) DO NOTHING`, GlobalChat, marker,
+	); err != nil {
+		return fmt.Errorf("❌ Erro ao marcar importação de recordes: %w", err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,275 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const broadcastSchema = `
+CREATE TABLE IF NOT EXISTS bot_broadcast_lists (
+  name       TEXT PRIMARY KEY,
+  created_by TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS bot_broadcast_members (
+  list_name TEXT NOT NULL REFERENCES bot_broadcast_lists(name) ON DELETE CASCADE,
+  jid       TEXT NOT NULL,
+  PRIMARY KEY (list_name, jid)
+);
+CREATE TABLE IF NOT EXISTS bot_broadcasts (
+  id         BIGSERIAL PRIMARY KEY,
+  list_name  TEXT NOT NULL,
+  content    TEXT NOT NULL,
+  created_by TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS bot_broadcast_deliveries (
+  broadcast_id BIGINT NOT NULL REFERENCES bot_broadcasts(id) ON DELETE CASCADE,
+  recipient    TEXT NOT NULL,
+  outbox_id    BIGINT REFERENCES bot_outbox(id) ON DELETE SET NULL,
+  PRIMARY KEY (broadcast_id, recipient)
+);
+`
+
+// BroadcastList resume uma lista de transmissão e a quantidade de membros
+type BroadcastList struct {
+	Name      string
+	CreatedBy string
+	Members   int
+}
+
+// Broadcast representa um envio feito para uma lista
+type Broadcast struct {
+	ID        int64
+	ListName  string
+	Content   string
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// BroadcastReport consolida o estado de entrega de um broadcast
+type BroadcastReport struct {
+	Broadcast
+	Total     int
+	Pending   int
+	Sent      int
+	Delivered int
+	Read      int
+	Failed    int
+}
+
+// CreateBroadcastList cria uma lista vazia
+func CreateBroadcastList(ctx context.Context, name, createdBy string) error {
+	res, err := DB.ExecContext(ctx,
+		`INSERT INTO bot_broadcast_lists (name, created_by) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		name, createdBy,
+	)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao criar lista: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("⚠️ A lista '%s' já existe", name)
+	}
+	return nil
+}
+
+// DeleteBroadcastList apaga a lista e seus membros
+func DeleteBroadcastList(ctx context.Context, name string) error {
+	res, err := DB.ExecContext(ctx, `DELETE FROM bot_broadcast_lists WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao apagar lista: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("⚠️ Lista '%s' não encontrada", name)
+	}
+	return nil
+}
+
+// ListBroadcastLists retorna todas as listas com a contagem de membros
+func ListBroadcastLists(ctx context.Context) ([]BroadcastList, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT l.name, l.created_by, count(m.jid)
+		FROM bot_broadcast_lists l
+		LEFT JOIN bot_broadcast_members m ON m.list_name = l.name
+		GROUP BY l.name, l.created_by
+		ORDER BY l.name`)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar listas: %w", err)
+	}
+	defer rows.Close()
+
+	var lists []BroadcastList
+	for rows.Next() {
+		var l BroadcastList
+		if err := rows.Scan(&l.Name, &l.CreatedBy, &l.Members); err != nil {
+			return nil, err
+		}
+		lists = append(lists, l)
+	}
+	return lists, rows.Err()
+}
+
+// AddBroadcastMembers inclui JIDs (números ou grupos) na lista, ignorando duplicados
+func AddBroadcastMembers(ctx context.Context, name string, jids []string) (int, error) {
+	if err := ensureBroadcastList(ctx, name); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, jid := range jids {
+		res, err := DB.ExecContext(ctx,
+			`INSERT INTO bot_broadcast_members (list_name, jid) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			name, jid,
+		)
+		if err != nil {
+			return added, fmt.Errorf("❌ Erro ao adicionar membro %s: %w", jid, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// RemoveBroadcastMembers retira JIDs da lista
+func RemoveBroadcastMembers(ctx context.Context, name string, jids []string) (int, error) {
+	if err := ensureBroadcastList(ctx, name); err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, jid := range jids {
+		res, err := DB.ExecContext(ctx,
+			`DELETE FROM bot_broadcast_members WHERE list_name = $1 AND jid = $2`, name, jid,
+		)
+		if err != nil {
+			return removed, fmt.Errorf("❌ Erro ao remover membro %s: %w", jid, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// BroadcastMembers retorna os JIDs de uma lista
+func BroadcastMembers(ctx context.Context, name string) ([]string, error) {
+	if err := ensureBroadcastList(ctx, name); err != nil {
+		return nil, err
+	}
+
+	rows, err := DB.QueryContext(ctx,
+		`SELECT jid FROM bot_broadcast_members WHERE list_name = $1 ORDER BY jid`, name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar membros: %w", err)
+	}
+	defer rows.Close()
+
+	var jids []string
+	for rows.Next() {
+		var jid string
+		if err := rows.Scan(&jid); err != nil {
+			return nil, err
+		}
+		jids = append(jids, jid)
+	}
+	return jids, rows.Err()
+}
+
+// CreateBroadcast registra um novo envio para a lista e retorna seu ID
+func CreateBroadcast(ctx context.Context, name, content, createdBy string) (int64, error) {
+	var id int64
+	err := DB.QueryRowContext(ctx,
+		`INSERT INTO bot_broadcasts (list_name, content, created_by) VALUES ($1, $2, $3) RETURNING id`,
+		name, content, createdBy,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Erro ao registrar broadcast: %w", err)
+	}
+	return id, nil
+}
+
+// AddBroadcastDelivery vincula um destinatário do broadcast à mensagem da fila de saída
+func AddBroadcastDelivery(ctx context.Context, broadcastID int64, recipient string, outboxID int64) error {
+	var ref sql.NullInt64
+	if outboxID > 0 {
+		ref = sql.NullInt64{Int64: outboxID, Valid: true}
+	}
+	_, err := DB.ExecContext(ctx,
+		`INSERT INTO bot_broadcast_deliveries (broadcast_id, recipient, outbox_id) VALUES ($1, $2, $3)
+		 ON CONFLICT (broadcast_id, recipient) DO UPDATE SET outbox_id = EXCLUDED.outbox_id`,
+		broadcastID, recipient, ref,
+	)
+	return err
+}
+
+// GetBroadcastReport calcula o estado de entrega e leitura de um broadcast
+func GetBroadcastReport(ctx context.Context, id int64) (*BroadcastReport, error) {
+	var r BroadcastReport
+	err := DB.QueryRowContext(ctx,
+		`SELECT id, list_name, content, created_by, created_at FROM bot_broadcasts WHERE id = $1`, id,
+	).Scan(&r.ID, &r.ListName, &r.Content, &r.CreatedBy, &r.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("⚠️ Broadcast #%d não encontrado", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar broadcast: %w", err)
+	}
+
+	// Destinatários sem mensagem na fila (falha ao enfileirar) contam como falha
+	err = DB.QueryRowContext(ctx, `
+		SELECT count(*),
+		       count(*) FILTER (WHERE o.status = $2),
+		       count(*) FILTER (WHERE o.status = $3),
+		       count(o.delivered_at),
+		       count(o.read_at),
+		       count(*) FILTER (WHERE o.id IS NULL OR o.status = $4)
+		FROM bot_broadcast_deliveries d
+		LEFT JOIN bot_outbox o ON o.id = d.outbox_id
+		WHERE d.broadcast_id = $1`,
+		id, OutboxPending, OutboxSent, OutboxFailed,
+	).Scan(&r.Total, &r.Pending, &r.Sent, &r.Delivered, &r.Read, &r.Failed)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar entregas: %w", err)
+	}
+	return &r, nil
+}
+
+// RecentBroadcasts retorna os últimos envios realizados
+func RecentBroadcasts(ctx context.Context, limit int) ([]Broadcast, error) {
+	rows, err := DB.QueryContext(ctx,
+		`SELECT id, list_name, content, created_by, created_at FROM bot_broadcasts ORDER BY id DESC LIMIT $1`, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar broadcasts: %w", err)
+	}
+	defer rows.Close()
+
+	var list []Broadcast
+	for rows.Next() {
+		var b Broadcast
+		if err := rows.Scan(&b.ID, &b.ListName, &b.Content, &b.CreatedBy, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, b)
+	}
+	return list, rows.Err()
+}
+
+func ensureBroadcastList(ctx context.Context, name string) error {
+	var exists bool
+	err := DB.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM bot_broadcast_lists WHERE name = $1)`, name,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao consultar lista: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("⚠️ Lista '%s' não encontrada", name)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const chatSettingsSchema = `
+CREATE TABLE IF NOT EXISTS bot_chat_settings (
+  chat       TEXT NOT NULL,
+  key        TEXT NOT NULL,
+  value      TEXT NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (chat, key)
+);
+`
+
+// GlobalChat é a "conversa" das preferências do bot como um todo (ex: ajustes do monitor)
+const GlobalChat = "*"
+
+// GetChatSetting retorna uma preferência da conversa; ok=false quando não definida
+func GetChatSetting(ctx context.Context, chat, key string) (string, bool, error) {
+	var value string
+	err := DB.QueryRowContext(ctx,
+		`SELECT value FROM bot_chat_settings WHERE chat = $1 AND key = $2`, chat, key,
+	).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("❌ Erro ao consultar preferência '%s': %w", key, err)
+	}
+	return value, true, nil
+}
+
+// SetChatSetting grava (ou substitui) uma preferência da conversa
+func SetChatSetting(ctx context.Context, chat, key, value string) error {
+	_, err := DB.ExecContext(ctx, `
+		INSERT INTO bot_chat_settings (chat, key, value) VALUES ($1, $2, $3)
+		ON CONFLICT (chat, key) DO UPDATE SET value = EXCLUDED.value, updated_at = now()`,
+		chat, key, value,
+	)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao salvar preferência '%s': %w", key, err)
+	}
+	return nil
+}
+
+// DeleteChatSetting remove uma preferência da conversa
+func DeleteChatSetting(ctx context.Context, chat, key string) error {
+	if _, err := DB.ExecContext(ctx,
+		`DELETE FROM bot_chat_settings WHERE chat = $1 AND key = $2`, chat, key,
+	); err != nil {
+		return fmt.Errorf("❌ Erro ao remover preferência '%s': %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const coinsSchema = `
+CREATE TABLE IF NOT EXISTS bot_coins (
+  id         TEXT PRIMARY KEY,
+  symbol     TEXT NOT NULL,
+  name       TEXT NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE bot_coins ADD COLUMN IF NOT EXISTS rank INTEGER NOT NULL DEFAULT 0;
+`
+
+// CoinEntry é uma moeda do diretório do CoinGecko (/coins/list); Rank 0 = sem rank de market cap
+type CoinEntry struct {
+	ID     string
+	Symbol string
+	Name   string
+	Rank   int
+}
+
+// LoadCoins retorna o diretório de moedas salvo e a data da última atualização
+func LoadCoins(ctx context.Context) ([]CoinEntry, time.Time, error) {
+	rows, err := DB.QueryContext(ctx, `SELECT id, symbol, name, rank, updated_at FROM bot_coins`)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("❌ Erro ao carregar diretório de moedas: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		coins   []CoinEntry
+		updated time.Time
+	)
+	for rows.Next() {
+		var c CoinEntry
+		var at time.Time
+		if err := rows.Scan(&c.ID, &c.Symbol, &c.Name, &c.Rank, &at); err != nil {
+			return nil, time.Time{}, err
+		}
+		if at.After(updated) {
+			updated = at
+		}
+		coins = append(coins, c)
+	}
+	return coins, updated, rows.Err()
+}
+
+// ReplaceCoins substitui o diretório salvo pela lista informada, em uma única transação
+func ReplaceCoins(ctx context.Context, coins []CoinEntry) error {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bot_coins`); err != nil {
+		return fmt.Errorf("❌ Erro ao limpar diretório de moedas: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("bot_coins", "id", "symbol", "name", "rank"))
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao preparar cópia do diretório: %w", err)
+	}
+
+	seen := make(map[string]bool, len(coins))
+	for _, c := range coins {
+		if c.ID == "" || seen[c.ID] {
+			continue
+		}
+		seen[c.ID] = true
+		if _, err := stmt.ExecContext(ctx, c.ID, c.Symbol, c.Name, c.Rank); err != nil {
+			stmt.Close()
+			return fmt.Errorf("❌ Erro ao gravar moeda %s: %w", c.ID, err)
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return fmt.Errorf("❌ Erro ao finalizar cópia do diretório: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const deferredSchema = `
+CREATE TABLE IF NOT EXISTS bot_deferred_messages (
+  id         BIGSERIAL PRIMARY KEY,
+  chat       TEXT NOT NULL,
+  body       TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS bot_deferred_messages_chat_idx ON bot_deferred_messages (chat, id);
+ALTER TABLE bot_deferred_messages ADD COLUMN IF NOT EXISTS mentions TEXT[] NOT NULL DEFAULT '{}';
+`
+
+// DeferredMessage é um aviso segurado durante o horário de silêncio da conversa
+type DeferredMessage struct {
+	ID        int64
+	Chat      string
+	Body      string
+	Mentions  []string // JIDs marcados no aviso (ex: quem criou o alerta, em grupos)
+	CreatedAt time.Time
+}
+
+// DeferMessage guarda o aviso para ser entregue quando o silêncio da conversa terminar
+func DeferMessage(ctx context.Context, chat, body string, mentions []string) (int64, error) {
+	if mentions == nil {
+		mentions = []string{}
+	}
+	var id int64
+	err := DB.QueryRowContext(ctx,
+		`INSERT INTO bot_deferred_messages (chat, body, mentions) VALUES ($1, $2, $3) RETURNING id`,
+		chat, body, pq.Array(mentions),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Erro ao adiar mensagem: %w", err)
+	}
+	return id, nil
+}
+
+// DeferredChats retorna as conversas com avisos adiados
+func DeferredChats(ctx context.Context) ([]string, error) {
+	rows, err := DB.QueryContext(ctx, `SELECT DISTINCT chat FROM bot_deferred_messages ORDER BY chat`)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar mensagens adiadas: %w", err)
+	}
+	defer rows.Close()
+
+	var chats []string
+	for rows.Next() {
+		var chat string
+		if err := rows.Scan(&chat); err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
+	return chats, rows.Err()
+}
+
+// CountDeferred informa quantos avisos a conversa tem adiados
+func CountDeferred(ctx context.Context, chat string) (int, error) {
+	var n int
+	if err := DB.QueryRowContext(ctx, `SELECT count(*) FROM bot_deferred_messages WHERE chat = $1`, chat).Scan(&n); err != nil {
+		return 0, fmt.Errorf("❌ Erro ao contar mensagens adiadas: %w", err)
+	}
+	return n, nil
+}
+
+// DrainDeferred entrega, em ordem cronológica, os avisos adiados da conversa e só os remove se
+// deliver der certo; com erro, eles continuam guardados para a próxima tentativa. Os avisos ficam
+// travados durante a entrega, então duas entregas simultâneas nunca levam o mesmo aviso.
+func DrainDeferred(ctx context.Context, chat string, deliver func([]DeferredMessage) error) (int, error) {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, chat, body, mentions, created_at FROM bot_deferred_messages
+		WHERE chat = $1 ORDER BY id FOR UPDATE SKIP LOCKED`, chat)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Erro ao consultar mensagens adiadas: %w", err)
+	}
+
+	var msgs []DeferredMessage
+	var ids []int64
+	for rows.Next() {
+		var m DeferredMessage
+		if err := rows.Scan(&m.ID, &m.Chat, &m.Body, pq.Array(&m.Mentions), &m.CreatedAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("❌ Erro ao ler mensagem adiada: %w", err)
+		}
+		msgs = append(msgs, m)
+		ids = append(ids, m.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(msgs) == 0 {
+		return 0, nil
+	}
+
+	if err := deliver(msgs); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bot_deferred_messages WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+		return 0, fmt.Errorf("❌ Erro ao retirar mensagens adiadas: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("❌ Erro ao retirar mensagens adiadas: %w", err)
+	}
+	return len(msgs), nil
+}
@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Status possíveis de uma mensagem na fila de saída
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed"
+)
+
+const outboxSchema = `
+CREATE TABLE IF NOT EXISTS bot_outbox (
+  id              BIGSERIAL PRIMARY KEY,
+  recipient       TEXT NOT NULL,
+  payload         BYTEA NOT NULL,
+  preview         TEXT NOT NULL DEFAULT '',
+  status          TEXT NOT NULL DEFAULT 'pending',
+  attempts        INTEGER NOT NULL DEFAULT 0,
+  last_error      TEXT NOT NULL DEFAULT '',
+  message_id      TEXT NOT NULL DEFAULT '',
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+  sent_at         TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS bot_outbox_due_idx ON bot_outbox (status, next_attempt_at);
+ALTER TABLE bot_outbox ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMPTZ;
+ALTER TABLE bot_outbox ADD COLUMN IF NOT EXISTS read_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS bot_outbox_message_id_idx ON bot_outbox (message_id);
+`
+
+// OutboxMessage representa uma mensagem persistida na fila de saída
+type OutboxMessage struct {
+	ID            int64
+	Recipient     string
+	Payload       []byte
+	Preview       string
+	Status        string
+	Attempts      int
+	LastError     string
+	MessageID     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        *time.Time
+	DeliveredAt   *time.Time
+	ReadAt        *time.Time
+}
+
+const outboxColumns = `id, recipient, payload, preview, status, attempts, last_error, message_id, next_attempt_at, created_at, sent_at, delivered_at, read_at`
+
+// EnqueueOutbox grava uma nova mensagem pendente e retorna seu ID
+func EnqueueOutbox(ctx context.Context, recipient string, payload []byte, preview string) (int64, error) {
+	var id int64
+	err := DB.QueryRowContext(ctx,
+		`INSERT INTO bot_outbox (recipient, payload, preview) VALUES ($1, $2, $3) RETURNING id`,
+		recipient, payload, preview,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Erro ao enfileirar mensagem: %w", err)
+	}
+	return id, nil
+}
+
+// DueOutbox retorna as mensagens pendentes cujo horário de envio já chegou, na ordem de criação
+func DueOutbox(ctx context.Context, limit int) ([]OutboxMessage, error) {
+	rows, err := DB.QueryContext(ctx,
+		`SELECT `+outboxColumns+` FROM bot_outbox
+		 WHERE status = $1 AND next_attempt_at <= now()
+		 ORDER BY id LIMIT $2`,
+		OutboxPending, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar fila de saída: %w", err)
+	}
+	defer rows.Close()
+
+	var list []OutboxMessage
+	for rows.Next() {
+		m, err := scanOutbox(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *m)
+	}
+	return list, rows.Err()
+}
+
+// GetOutbox busca uma mensagem da fila pelo ID
+func GetOutbox(ctx context.Context, id int64) (*OutboxMessage, error) {
+	row := DB.QueryRowContext(ctx, `SELECT `+outboxColumns+` FROM bot_outbox WHERE id = $1`, id)
+	m, err := scanOutbox(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("⚠️ Mensagem #%d não encontrada na fila", id)
+	}
+	return m, err
+}
+
+// SetOutboxMessageID grava o ID da mensagem no WhatsApp antes da primeira tentativa de envio,
+// para que as retentativas (inclusive depois de um reinício) reutilizem o mesmo ID
+func SetOutboxMessageID(ctx context.Context, id int64, messageID string) error {
+	_, err := DB.ExecContext(ctx,
+		`UPDATE bot_outbox SET message_id = $2 WHERE id = $1 AND message_id = ''`,
+		id, messageID,
+	)
+	return err
+}
+
+// MarkOutboxSent registra o envio bem-sucedido e o ID da mensagem no WhatsApp
+func MarkOutboxSent(ctx context.Context, id int64, messageID string) error {
+	_, err := DB.ExecContext(ctx,
+		`UPDATE bot_outbox SET status = $2, message_id = $3, attempts = attempts + 1, last_error = '', sent_at = now() WHERE id = $1`,
+		id, OutboxSent, messageID,
+	)
+	return err
+}
+
+// MarkOutboxRetry reagenda a mensagem após uma falha transitória
+func MarkOutboxRetry(ctx context.Context, id int64, next time.Time, cause string, countAttempt bool) error {
+	inc := 0
+	if countAttempt {
+		inc = 1
+	}
+	_, err := DB.ExecContext(ctx,
+		`UPDATE bot_outbox SET attempts = attempts + $2, last_error = $3, next_attempt_at = $4 WHERE id = $1`,
+		id, inc, cause, next,
+	)
+	return err
+}
+
+// MarkOutboxFailed encerra as tentativas de envio da mensagem
+func MarkOutboxFailed(ctx context.Context, id int64, cause string) error {
+	_, err := DB.ExecContext(ctx,
+		`UPDATE bot_outbox SET status = $2, attempts = attempts + 1, last_error = $3 WHERE id = $1`,
+		id, OutboxFailed, cause,
+	)
+	return err
+}
+
+// MarkOutboxDelivered registra a confirmação de entrega das mensagens com os IDs informados
+func MarkOutboxDelivered(ctx context.Context, messageIDs []string, at time.Time) error {
+	_, err := DB.ExecContext(ctx,
+		`UPDATE bot_outbox SET delivered_at = COALESCE(delivered_at, $2) WHERE message_id = ANY($1)`,
+		pq.Array(messageIDs), at,
+	)
+	return err
+}
+
+// MarkOutboxRead registra a confirmação de leitura (que também implica entrega)
+func MarkOutboxRead(ctx context.Context, messageIDs []string, at time.Time) error {
+	_, err := DB.ExecContext(ctx,
+		`UPDATE bot_outbox SET delivered_at = COALESCE(delivered_at, $2), read_at = COALESCE(read_at, $2) WHERE message_id = ANY($1)`,
+		pq.Array(messageIDs), at,
+	)
+	return err
+}
+
+// OutboxStats conta as mensagens da fila agrupadas por status
+func OutboxStats(ctx context.Context) (map[string]int, error) {
+	rows, err := DB.QueryContext(ctx, `SELECT status, count(*) FROM bot_outbox GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar estatísticas da fila: %w", err)
+	}
+	defer rows.Close()
+
+	stats := map[string]int{}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		stats[status] = count
+	}
+	return stats, rows.Err()
+}
+
+//
+// === 🧠 Utilitários Internos ===
+//
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOutbox(row rowScanner) (*OutboxMessage, error) {
+	var m OutboxMessage
+	var sentAt, deliveredAt, readAt sql.NullTime
+	err := row.Scan(&m.ID, &m.Recipient, &m.Payload, &m.Preview, &m.Status, &m.Attempts,
+		&m.LastError, &m.MessageID, &m.NextAttemptAt, &m.CreatedAt, &sentAt, &deliveredAt, &readAt)
+	if err != nil {
+		return nil, err
+	}
+	m.SentAt = nullTime(sentAt)
+	m.DeliveredAt = nullTime(deliveredAt)
+	m.ReadAt = nullTime(readAt)
+	return &m, nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
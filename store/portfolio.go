@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const portfolioSchema = `
+CREATE TABLE IF NOT EXISTS bot_portfolio_transactions (
+  id         BIGSERIAL PRIMARY KEY,
+  owner      TEXT NOT NULL,
+  coin_id    TEXT NOT NULL,
+  symbol     TEXT NOT NULL,
+  quantity   DOUBLE PRECISION NOT NULL CHECK (quantity <> 0),
+  price_brl  DOUBLE PRECISION NOT NULL,
+  price_usd  DOUBLE PRECISION NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS bot_portfolio_transactions_owner_idx ON bot_portfolio_transactions (owner, id);
+`
+
+// PortfolioTransaction é uma compra (Quantity > 0) ou venda (Quantity < 0) registrada com !carteira.
+// O preço unitário é guardado em BRL e USD pelo câmbio do momento, para o custo médio nas duas moedas.
+type PortfolioTransaction struct {
+	ID        int64
+	Owner     string // JID de quem registrou (a carteira é pessoal, mesmo quando criada em grupo)
+	CoinID    string
+	Symbol    string
+	Quantity  float64
+	PriceBRL  float64
+	PriceUSD  float64
+	CreatedAt time.Time
+}
+
+// AddPortfolioTransaction grava a transação e devolve o ID gerado
+func AddPortfolioTransaction(ctx context.Context, t PortfolioTransaction) (int64, error) {
+	var id int64
+	err := DB.QueryRowContext(ctx, `
+		INSERT INTO bot_portfolio_transactions (owner, coin_id, symbol, quantity, price_brl, price_usd)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		t.Owner, t.CoinID, t.Symbol, t.Quantity, t.PriceBRL, t.PriceUSD,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Erro ao registrar transação: %w", err)
+	}
+	return id, nil
+}
+
+// ListPortfolioTransactions retorna as transações do usuário em ordem cronológica
+func ListPortfolioTransactions(ctx context.Context, owner string) ([]PortfolioTransaction, error) {
+	rows, err := DB.QueryContext(ctx, `
+		SELECT id, owner, coin_id, symbol, quantity, price_brl, price_usd, created_at
+		FROM bot_portfolio_transactions WHERE owner = $1 ORDER BY id`, owner)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar carteira: %w", err)
+	}
+	defer rows.Close()
+
+	var txs []PortfolioTransaction
+	for rows.Next() {
+		var t PortfolioTransaction
+		if err := rows.Scan(&t.ID, &t.Owner, &t.CoinID, &t.Symbol, &t.Quantity, &t.PriceBRL, &t.PriceUSD, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("❌ Erro ao ler transação: %w", err)
+		}
+		txs = append(txs, t)
+	}
+	return txs, rows.Err()
+}
+
+// DeletePortfolioTransaction apaga uma transação do usuário; transações de outros não são afetadas
+func DeletePortfolioTransaction(ctx context.Context, owner string, id int64) error {
+	res, err := DB.ExecContext(ctx, `DELETE FROM bot_portfolio_transactions WHERE id = $1 AND owner = $2`, id, owner)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao remover transação: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("⚠️ Transação #%d não encontrada na sua carteira", id)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// DB é a conexão PostgreSQL compartilhada pelas tabelas próprias do bot (fora do WhatsMeow).
+// Fica nil até InitWhatsAppClient conectar ao banco.
+var DB *sql.DB
+
+// botSchema contém as tabelas do bot, aplicadas de forma idempotente na inicialização
+var botSchema = []string{
+	outboxSchema,
+	broadcastSchema,
+	coinsSchema,
+	chatSettingsSchema,
+	alertsSchema,
+	athSchema,
+	subscriptionsSchema,
+	portfolioSchema,
+	deferredSchema,
+}
+
+// Migrate cria/verifica as tabelas do bot no banco compartilhado
+func Migrate(ctx context.Context) error {
+	if DB == nil {
+		return fmt.Errorf("❌ Banco de dados não inicializado")
+	}
+
+	for _, stmt := range botSchema {
+		if _, err := DB.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("❌ Erro ao aplicar schema do bot: %w", err)
+		}
+	}
+
+	log.Println("🧱 Tabelas do bot criadas/verificadas com sucesso.")
+	return nil
+}
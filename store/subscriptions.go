@@ -0,0 +1,138 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const subscriptionsSchema = `
+CREATE TABLE IF NOT EXISTS bot_subscriptions (
+  chat       TEXT NOT NULL,
+  event      TEXT NOT NULL,
+  coin       TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (chat, event, coin)
+);
+CREATE INDEX IF NOT EXISTS bot_subscriptions_event_idx ON bot_subscriptions (event, coin);
+`
+
+// AllCoins é a moeda das assinaturas que valem para todas as moedas do evento
+const AllCoins = "*"
+
+// Subscription é a inscrição de uma conversa em um tipo de aviso (ex: ath) de uma moeda
+type Subscription struct {
+	Chat      string
+	Event     string
+	Coin      string // ID da moeda ou AllCoins
+	CreatedAt time.Time
+}
+
+// Subscribe inscreve a conversa no evento para as moedas informadas (inscrições repetidas são ignoradas)
+func Subscribe(ctx context.Context, chat, event string, coins []string) error {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("❌ Erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, coin := range coins {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO bot_subscriptions (chat, event, coin) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			chat, event, coin,
+		); err != nil {
+			return fmt.Errorf("❌ Erro ao salvar assinatura: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Unsubscribe remove as inscrições da conversa no evento; sem moedas, remove todas as do evento
+func Unsubscribe(ctx context.Context, chat, event string, coins []string) (int64, error) {
+	query := `DELETE FROM bot_subscriptions WHERE chat = $1 AND event = $2`
+	args := []any{chat, event}
+	if len(coins) > 0 {
+		query += ` AND coin = ANY($3)`
+		args = append(args, pq.Array(coins))
+	}
+
+	res, err := DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("❌ Erro ao cancelar assinatura: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// ListSubscriptions retorna as inscrições da conversa
+func ListSubscriptions(ctx context.Context, chat string) ([]Subscription, error) {
+	rows, err := DB.QueryContext(ctx,
+		`SELECT chat, event, coin, created_at FROM bot_subscriptions WHERE chat = $1 ORDER BY event, coin`, chat)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar assinaturas: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []Subscription
+	for rows.Next() {
+		var s Subscription
+		if err := rows.Scan(&s.Chat, &s.Event, &s.Coin, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("❌ Erro ao ler assinatura: %w", err)
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+// Subscribers retorna as conversas inscritas no evento para a moeda (diretamente ou em todas)
+func Subscribers(ctx context.Context, event, coin string) ([]string, error) {
+	rows, err := DB.QueryContext(ctx,
+		`SELECT DISTINCT chat FROM bot_subscriptions WHERE event = $1 AND coin IN ($2, $3) ORDER BY chat`,
+		event, coin, AllCoins)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar assinantes: %w", err)
+	}
+	defer rows.Close()
+
+	var chats []string
+	for rows.Next() {
+		var chat string
+		if err := rows.Scan(&chat); err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
+	return chats, rows.Err()
+}
+
+// SeedSubscriptions inscreve as conversas no evento (todas as moedas) uma única vez, marcando
+// a carga inicial (marker) na mesma transação; devolve false se ela já tinha sido feita
+func SeedSubscriptions(ctx context.Context, chats []string, event, marker string) (bool, error) {
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("❌ Erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO bot_chat_settings (chat, key, value) VALUES ($1, $2, now()::text)
+		ON CONFLICT (chat, key) DO NOTHING`, GlobalChat, marker)
+	if err != nil {
+		return false, fmt.Errorf("❌ Erro ao marcar carga de assinaturas: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	for _, chat := range chats {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO bot_subscriptions (chat, event, coin) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			chat, event, AllCoins,
+		); err != nil {
+			return false, fmt.Errorf("❌ Erro ao salvar assinatura: %w", err)
+		}
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,112 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// SentMessage é um envio registrado pelo transporte em memória
+type SentMessage struct {
+	To        types.JID
+	Message   *proto.Message
+	Preview   string
+	MessageID string
+}
+
+// Memory implementa Deliverer sem rede: guarda tudo que foi enviado.
+// Útil para testes de comandos e para rodar o bot sem WhatsApp.
+type Memory struct {
+	mu      sync.Mutex
+	sent    []SentMessage
+	nextID  int
+	Groups  map[types.JID][]types.JID // participantes por grupo
+	Offline bool                      // simula cliente desconectado
+}
+
+// NewMemory cria um transporte em memória vazio
+func NewMemory() *Memory {
+	return &Memory{Groups: map[types.JID][]types.JID{}}
+}
+
+// NewMemoryConversation cria uma conversa recebida em memória (sem evento do WhatsApp)
+func NewMemoryConversation(m *Memory, chat, sender types.JID, text string) Conversation {
+	return NewConversation(m, chat, sender, text, nil)
+}
+
+func (m *Memory) Send(ctx context.Context, to types.JID, msg *proto.Message, _ string) error {
+	return m.Deliver(ctx, to, msg, m.NewMessageID())
+}
+
+func (m *Memory) Deliver(_ context.Context, to types.JID, msg *proto.Message, messageID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Offline {
+		return whatsmeow.ErrNotConnected
+	}
+
+	preview := msg.GetConversation()
+	if preview == "" {
+		preview = msg.GetExtendedTextMessage().GetText()
+	}
+	m.sent = append(m.sent, SentMessage{To: to, Message: msg, Preview: preview, MessageID: messageID})
+	return nil
+}
+
+func (m *Memory) NewMessageID() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	return fmt.Sprintf("MEM%06d", m.nextID)
+}
+
+func (m *Memory) Connected() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.Offline
+}
+
+// Upload não envia nada: devolve referências falsas com o tamanho do arquivo
+func (m *Memory) Upload(_ context.Context, data []byte, _ whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+	return whatsmeow.UploadResponse{
+		URL:        "memory://upload",
+		DirectPath: "/memory/upload",
+		FileLength: uint64(len(data)),
+	}, nil
+}
+
+func (m *Memory) GroupParticipants(_ context.Context, group types.JID) ([]types.JID, error) {
+	if group.Server != types.GroupServer {
+		return nil, errNotGroup(group)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]types.JID(nil), m.Groups[group]...), nil
+}
+
+// Sent retorna uma cópia de tudo que foi enviado até agora
+func (m *Memory) Sent() []SentMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SentMessage(nil), m.sent...)
+}
+
+// Texts retorna apenas os textos enviados, na ordem
+func (m *Memory) Texts() []string {
+	var texts []string
+	for _, s := range m.Sent() {
+		texts = append(texts, s.Preview)
+	}
+	return texts
+}
+
+// Reset descarta o histórico de envios
+func (m *Memory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = nil
+}
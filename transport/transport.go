@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	p "google.golang.org/protobuf/proto"
+)
+
+//
+// ========== 📡 Interfaces =========
+//
+
+// Messenger é o mínimo que comandos e serviços precisam para falar com o usuário
+type Messenger interface {
+	// Send entrega uma mensagem já montada; preview é o resumo usado em logs e na fila
+	Send(ctx context.Context, to types.JID, msg *proto.Message, preview string) error
+	// Upload envia mídia (criptografada) e devolve as referências para montar a mensagem
+	Upload(ctx context.Context, data []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error)
+	// GroupParticipants lista os participantes de um grupo, sem o próprio bot
+	GroupParticipants(ctx context.Context, group types.JID) ([]types.JID, error)
+}
+
+// Deliverer é o transporte bruto usado pela fila de saída: envio com ID definido e estado da conexão
+type Deliverer interface {
+	Messenger
+	Deliver(ctx context.Context, to types.JID, msg *proto.Message, messageID string) error
+	NewMessageID() string
+	Connected() bool
+}
+
+// Conversation representa uma mensagem recebida e como respondê-la
+type Conversation interface {
+	Messenger() Messenger
+	Text() string
+	Sender() types.JID
+	Chat() types.JID
+	IsGroup() bool
+	// Event é o evento original do WhatsApp (nil em conversas em memória)
+	Event() *events.Message
+	Reply(ctx context.Context, text string)
+	ReplyMentions(ctx context.Context, text string, mentions []types.JID)
+}
+
+//
+// ========== 💬 Conversa =========
+//
+
+type conversation struct {
+	messenger Messenger
+	text      string
+	sender    types.JID
+	chat      types.JID
+	isGroup   bool
+	event     *events.Message
+}
+
+// NewConversation cria uma conversa a partir dos dados já extraídos da mensagem recebida
+func NewConversation(m Messenger, chat, sender types.JID, text string, event *events.Message) Conversation {
+	return &conversation{
+		messenger: m,
+		text:      text,
+		sender:    sender,
+		chat:      chat,
+		isGroup:   chat.Server == types.GroupServer,
+		event:     event,
+	}
+}
+
+func (c *conversation) Messenger() Messenger   { return c.messenger }
+func (c *conversation) Text() string           { return c.text }
+func (c *conversation) Sender() types.JID      { return c.sender }
+func (c *conversation) Chat() types.JID        { return c.chat }
+func (c *conversation) IsGroup() bool          { return c.isGroup }
+func (c *conversation) Event() *events.Message { return c.event }
+
+// Reply responde na mesma conversa (grupo ou privado)
+func (c *conversation) Reply(ctx context.Context, text string) {
+	c.ReplyMentions(ctx, text, nil)
+}
+
+// ReplyMentions responde marcando os JIDs informados
+func (c *conversation) ReplyMentions(ctx context.Context, text string, mentions []types.JID) {
+	if strings.TrimSpace(text) == "" {
+		log.Println("⚠️ Conteúdo vazio — mensagem não enviada.")
+		return
+	}
+
+	msg, preview := TextMessage(text, mentions)
+	if err := c.messenger.Send(ctx, c.chat, msg, preview); err != nil {
+		log.Printf("❌ Falha ao responder em %s: %v", c.chat.String(), err)
+	}
+}
+
+//
+// ========== 🧱 Montagem de texto =========
+//
+
+// mentionPlaceholder casa os marcadores @número já presentes no texto
+var mentionPlaceholder = regexp.MustCompile(`@(\d{6,20})`)
+
+// TextMessage monta uma mensagem de texto; com menções, usa ExtendedTextMessage + ContextInfo
+func TextMessage(body string, mentions []types.JID) (*proto.Message, string) {
+	if len(mentions) == 0 {
+		return &proto.Message{Conversation: p.String(body)}, body
+	}
+
+	body = MentionText(body, mentions)
+	list := make([]string, 0, len(mentions))
+	for _, jid := range mentions {
+		list = append(list, jid.ToNonAD().String())
+	}
+
+	return &proto.Message{ExtendedTextMessage: &proto.ExtendedTextMessage{
+		Text:        p.String(body),
+		ContextInfo: &proto.ContextInfo{MentionedJID: list},
+	}}, body
+}
+
+// MentionText garante um marcador @número no texto para cada JID mencionado.
+// Marcadores já escritos no texto são mantidos; os que faltam são anexados ao final.
+func MentionText(text string, mentions []types.JID) string {
+	present := map[string]bool{}
+	for _, m := range mentionPlaceholder.FindAllStringSubmatch(text, -1) {
+		present[m[1]] = true
+	}
+
+	var missing []string
+	for _, jid := range mentions {
+		if present[jid.User] {
+			continue
+		}
+		present[jid.User] = true
+		missing = append(missing, "@"+jid.User)
+	}
+
+	if len(missing) == 0 {
+		return text
+	}
+	return strings.TrimSpace(text) + "\n\n" + strings.Join(missing, " ")
+}
+
+// ExtractMentions converte os marcadores @número escritos no texto em JIDs de usuário
+func ExtractMentions(text string) []types.JID {
+	var jids []types.JID
+	seen := map[string]bool{}
+	for _, m := range mentionPlaceholder.FindAllStringSubmatch(text, -1) {
+		if seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		jids = append(jids, types.NewJID(m[1], types.DefaultUserServer))
+	}
+	return jids
+}
+
+func errNotGroup(jid types.JID) error {
+	return fmt.Errorf("⚠️ %s não é um grupo — este comando só funciona em grupos", jid.User)
+}
@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// WhatsApp implementa Deliverer sobre um cliente WhatsMeow conectado
+type WhatsApp struct {
+	client *whatsmeow.Client
+}
+
+// NewWhatsApp embrulha o cliente WhatsMeow
+func NewWhatsApp(client *whatsmeow.Client) *WhatsApp {
+	return &WhatsApp{client: client}
+}
+
+// Send envia imediatamente, com ID gerado pelo WhatsMeow
+func (w *WhatsApp) Send(ctx context.Context, to types.JID, msg *proto.Message, _ string) error {
+	_, err := w.client.SendMessage(ctx, to, msg, whatsmeow.SendRequestExtra{})
+	return err
+}
+
+// Deliver envia usando um ID fixo (retentativas da fila não duplicam a mensagem)
+func (w *WhatsApp) Deliver(ctx context.Context, to types.JID, msg *proto.Message, messageID string) error {
+	_, err := w.client.SendMessage(ctx, to, msg, whatsmeow.SendRequestExtra{ID: messageID})
+	return err
+}
+
+// NewMessageID gera um ID de mensagem no formato do WhatsApp
+func (w *WhatsApp) NewMessageID() string {
+	return w.client.GenerateMessageID()
+}
+
+// Connected informa se o cliente está conectado e autenticado
+func (w *WhatsApp) Connected() bool {
+	return w.client.IsConnected() && w.client.IsLoggedIn()
+}
+
+// Upload faz o upload criptografado da mídia para os servidores do WhatsApp
+func (w *WhatsApp) Upload(ctx context.Context, data []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+	return w.client.Upload(ctx, data, mediaType)
+}
+
+// GroupParticipants retorna os participantes do grupo, sem o próprio bot
+func (w *WhatsApp) GroupParticipants(_ context.Context, group types.JID) ([]types.JID, error) {
+	if group.Server != types.GroupServer {
+		return nil, errNotGroup(group)
+	}
+
+	info, err := w.client.GetGroupInfo(group)
+	if err != nil {
+		return nil, fmt.Errorf("❌ Erro ao consultar participantes do grupo: %w", err)
+	}
+
+	var self, selfLID string
+	if w.client.Store.ID != nil {
+		self = w.client.Store.ID.User
+	}
+	if !w.client.Store.LID.IsEmpty() {
+		selfLID = w.client.Store.LID.User
+	}
+
+	var jids []types.JID
+	for _, participant := range info.Participants {
+		if participant.JID.User == self || participant.JID.User == selfLID {
+			continue
+		}
+		jids = append(jids, participant.JID)
+	}
+	return jids, nil
+}
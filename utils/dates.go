@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts são os formatos absolutos aceitos, do mais ao menos comum
+var dateLayouts = []string{"02/01/2006", "2/1/2006", "02/01/06", "2/1/06", "02-01-2006", "2006-01-02", "02.01.2006"}
+
+// relativeDate reconhece "há 30 dias", "ha 2 semanas", "3 meses atrás", "1 ano atras"
+var relativeDate = regexp.MustCompile(`^(?:h[aá]\s+)?(\d+)\s+(dias?|semanas?|m[eê]s|meses|anos?)(?:\s+atr[aá]s)?$`)
+
+// ParseDate interpreta datas digitadas em pt-BR: "15/01/2024", "2024-01-15", "15/01" (último 15/01),
+// "hoje", "ontem", "anteontem" e expressões relativas como "há 30 dias". Devolve o início do dia
+// no fuso de now; datas futuras são rejeitadas.
+func ParseDate(input string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(input), " "))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var date time.Time
+	switch s {
+	case "":
+		return time.Time{}, fmt.Errorf("⚠️ Data vazia")
+	case "hoje":
+		date = today
+	case "ontem":
+		date = today.AddDate(0, 0, -1)
+	case "anteontem":
+		date = today.AddDate(0, 0, -2)
+	}
+
+	if date.IsZero() {
+		if m := relativeDate.FindStringSubmatch(s); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			switch {
+			case strings.HasPrefix(m[2], "dia"):
+				date = today.AddDate(0, 0, -n)
+			case strings.HasPrefix(m[2], "semana"):
+				date = today.AddDate(0, 0, -7*n)
+			case strings.HasPrefix(m[2], "ano"):
+				date = today.AddDate(-n, 0, 0)
+			default:
+				date = today.AddDate(0, -n, 0)
+			}
+		}
+	}
+
+	if date.IsZero() {
+		for _, layout := range dateLayouts {
+			if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+				date = t
+				break
+			}
+		}
+	}
+
+	// Dia e mês sem ano: a ocorrência mais recente (ano atual ou o anterior)
+	if date.IsZero() {
+		for _, layout := range []string{"02/01", "2/1"} {
+			if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+				date = time.Date(now.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+				if date.After(today) {
+					date = date.AddDate(-1, 0, 0)
+				}
+				break
+			}
+		}
+	}
+
+	if date.IsZero() {
+		return time.Time{}, fmt.Errorf("⚠️ Data '%s' inválida. Use 15/01/2024, ontem ou há 30 dias", input)
+	}
+	if date.After(today) {
+		return time.Time{}, fmt.Errorf("⚠️ A data %s ainda não chegou", date.Format("02/01/2006"))
+	}
+	return date, nil
+}
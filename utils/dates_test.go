@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"15/01/2024", day(2024, 1, 15), false},
+		{"5/1/2024", day(2024, 1, 5), false},
+		{"15/01/24", day(2024, 1, 15), false},
+		{"2024-01-15", day(2024, 1, 15), false},
+		{"15.01.2024", day(2024, 1, 15), false},
+		{"hoje", day(2024, 3, 10), false},
+		{"Ontem", day(2024, 3, 9), false},
+		{"anteontem", day(2024, 3, 8), false},
+		{"há 30 dias", day(2024, 2, 9), false},
+		{"ha 2 semanas", day(2024, 2, 25), false},
+		{"3 meses atrás", day(2023, 12, 10), false},
+		{"1 ano atras", day(2023, 3, 10), false},
+		{"15/01", day(2024, 1, 15), false},
+		{"25/12", day(2023, 12, 25), false}, // ainda não chegou este ano: vale o anterior
+		{"11/03/2024", time.Time{}, true},   // futuro
+		{"", time.Time{}, true},
+		{"31/02/2024", time.Time{}, true},
+		{"semana passada", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDate(%q) erro = %v, quero erro = %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q) = %s, quero %s", tt.in, got.Format("02/01/2006"), tt.want.Format("02/01/2006"))
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	mdHeading    = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.+?)\s*#*\s*$`)
+	mdRule       = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	mdBullet     = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdTableSep   = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	mdInlineCode = regexp.MustCompile("`([^`\n]+)`")
+	mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdBoldItalic = regexp.MustCompile(`\*\*\*(\S(?:.*?\S)?)\*\*\*`)
+	mdBoldStar   = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`)
+	mdItalicStar = regexp.MustCompile(`\*([^\s*](?:[^*\n]*?[^\s*])?)\*`)
+	mdBoldUnder  = regexp.MustCompile(`__(\S(?:.*?\S)?)__`)
+	mdStrike     = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+)
+
+// placeholders protegem trechos já convertidos (código) das regras inline
+const mdPlaceholder = "\x00%d\x00"
+
+// mdBoldMark marca o negrito já convertido, para que a regra de itálico não confunda o * do WhatsApp
+const mdBoldMark = "\x01"
+
+// MarkdownToWhatsApp converte markdown estilo GitHub (respostas da IA) para a marcação do WhatsApp:
+// **negrito** → *negrito*, *itálico* → _itálico_, ***ambos*** → *_ambos_*, ~~riscado~~ → ~riscado~,
+// `código` e blocos ``` → ```mono```, títulos viram linhas em negrito, tabelas são achatadas e
+// links são expandidos para "texto (url)".
+func MarkdownToWhatsApp(md string) string {
+	var protected []string
+	protect := func(s string) string {
+		protected = append(protected, s)
+		return fmt.Sprintf(mdPlaceholder, len(protected)-1)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+
+	var code []string
+	inCode := false
+	var table [][]string
+
+	flushTable := func() {
+		if len(table) > 0 {
+			out = append(out, flattenTable(table)...)
+			table = nil
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// 💻 Blocos de código: conteúdo preservado, sem linguagem
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				out = append(out, protect("```"+strings.Join(code, "\n")+"```"))
+				code, inCode = nil, false
+			} else {
+				flushTable()
+				inCode = true
+			}
+			continue
+		}
+		if inCode {
+			code = append(code, line)
+			continue
+		}
+
+		// 📊 Tabelas: acumula as linhas e achata ao final
+		if strings.HasPrefix(trimmed, "|") && strings.HasSuffix(trimmed, "|") {
+			if !mdTableSep.MatchString(trimmed) {
+				table = append(table, splitTableRow(convertInline(trimmed, protect)))
+			}
+			continue
+		}
+		flushTable()
+
+		switch {
+		case mdHeading.MatchString(line):
+			title := mdHeading.FindStringSubmatch(line)[1]
+			title = strings.ReplaceAll(convertInline(title, protect), "*", "")
+			out = append(out, "*"+title+"*")
+		case mdRule.MatchString(line):
+			out = append(out, "──────────────────────")
+		case mdBullet.MatchString(line):
+			m := mdBullet.FindStringSubmatch(line)
+			out = append(out, m[1]+"• "+convertInline(m[2], protect))
+		default:
+			out = append(out, convertInline(line, protect))
+		}
+	}
+
+	// Bloco de código sem fechamento: mantém o conteúdo como mono
+	if inCode {
+		out = append(out, protect("```"+strings.Join(code, "\n")+"```"))
+	}
+	flushTable()
+
+	result := strings.Join(out, "\n")
+	for i, s := range protected {
+		result = strings.Replace(result, fmt.Sprintf(mdPlaceholder, i), s, 1)
+	}
+	return strings.TrimSpace(result)
+}
+
+// convertInline aplica as regras de formatação dentro de uma linha
+func convertInline(s string, protect func(string) string) string {
+	s = mdInlineCode.ReplaceAllStringFunc(s, func(m string) string {
+		return protect("```" + mdInlineCode.FindStringSubmatch(m)[1] + "```")
+	})
+	s = mdImage.ReplaceAllStringFunc(s, func(m string) string {
+		parts := mdImage.FindStringSubmatch(m)
+		if parts[1] == "" {
+			return protect(parts[2])
+		}
+		return parts[1] + " (" + protect(parts[2]) + ")"
+	})
+	s = mdLink.ReplaceAllStringFunc(s, func(m string) string {
+		parts := mdLink.FindStringSubmatch(m)
+		if parts[1] == parts[2] {
+			return protect(parts[2])
+		}
+		return parts[1] + " (" + protect(parts[2]) + ")"
+	})
+	s = mdBoldItalic.ReplaceAllString(s, mdBoldMark+"_${1}_"+mdBoldMark)
+	s = mdBoldStar.ReplaceAllString(s, mdBoldMark+"$1"+mdBoldMark)
+	s = mdBoldUnder.ReplaceAllString(s, mdBoldMark+"$1"+mdBoldMark)
+	s = convertItalic(s)
+	s = mdStrike.ReplaceAllString(s, "~$1~")
+	return strings.ReplaceAll(s, mdBoldMark, "*")
+}
+
+// convertItalic troca *itálico* por _itálico_. O asterisco precisa estar na borda de uma palavra,
+// então contas como 2*3*4 ficam como estão (marcadores de lista já foram tratados por linha).
+func convertItalic(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range mdItalicStar.FindAllStringSubmatchIndex(s, -1) {
+		start, end := m[0], m[1]
+		if start > 0 && isWordByte(s[start-1]) || end < len(s) && isWordByte(s[end]) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString("_" + s[m[2]:m[3]] + "_")
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func splitTableRow(row string) []string {
+	cells := strings.Split(strings.Trim(row, "|"), "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// flattenTable transforma a tabela em linhas "Coluna: valor", uma linha por registro
+func flattenTable(rows [][]string) []string {
+	if len(rows) == 1 {
+		return []string{strings.Join(rows[0], " | ")}
+	}
+
+	header := rows[0]
+	var lines []string
+	for _, row := range rows[1:] {
+		var parts []string
+		for i, cell := range row {
+			if cell == "" {
+				continue
+			}
+			if i < len(header) && header[i] != "" {
+				parts = append(parts, "*"+strings.ReplaceAll(header[i], "*", "")+"*: "+cell)
+			} else {
+				parts = append(parts, cell)
+			}
+		}
+		lines = append(lines, "• "+strings.Join(parts, " | "))
+	}
+	return lines
+}
@@ -0,0 +1,36 @@
+package utils
+
+import "testing"
+
+func TestMarkdownToWhatsApp(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"negrito", "**forte**", "*forte*"},
+		{"negrito com sublinhado", "__forte__", "*forte*"},
+		{"itálico", "*leve*", "_leve_"},
+		{"negrito e itálico", "***ambos***", "*_ambos_*"},
+		{"negrito e itálico na mesma linha", "**a** e *b*", "*a* e _b_"},
+		{"vários itálicos", "*um* e *dois*", "_um_ e _dois_"},
+		{"riscado", "~~velho~~", "~velho~"},
+		{"conta com asteriscos", "2*3*4 = 24", "2*3*4 = 24"},
+		{"asterisco solto", "5 * 3 = 15", "5 * 3 = 15"},
+		{"lista com asterisco", "* item *destaque*", "• item _destaque_"},
+		{"lista com hífen", "- item", "• item"},
+		{"código preservado", "`*x*` e *y*", "```*x*``` e _y_"},
+		{"título", "## **Resumo**", "*Resumo*"},
+		{"link", "[site](https://x.com)", "site (https://x.com)"},
+		{"link igual ao texto", "[https://x.com](https://x.com)", "https://x.com"},
+		{"linha horizontal", "---", "──────────────────────"},
+		{"tabela", "| Moeda | Preço |\n|---|---|\n| BTC | 1 |", "• *Moeda*: BTC | *Preço*: 1"},
+		{"bloco de código", "```go\nx := *p\n```", "```x := *p```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownToWhatsApp(tt.in); got != tt.want {
+				t.Errorf("MarkdownToWhatsApp(%q) = %q, quero %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
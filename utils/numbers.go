@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// thousandsGroup é um separador único seguido de exatamente três dígitos (1.500, 600,000)
+	thousandsGroup = regexp.MustCompile(`^[1-9]\d{0,2}[.,]\d{3}$`)
+
+	// plainNumber é o formato aceito depois de normalizar os separadores (sem sinal, NaN ou Inf)
+	plainNumber = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)$`)
+)
+
+// ParseAmount interpreta valores positivos digitados no estilo brasileiro ou americano:
+// "1.234,56", "1,234.56", "0,05", "0.05", "R$ 500", "1.500" e "1.000.000".
+// Com os dois separadores, o último é o decimal; um separador repetido é de milhar, assim como um
+// separador único seguido de exatamente três dígitos ("1.500" e "1,500" valem 1500). Nos demais
+// casos o separador único é decimal ("1,5", "0.500" e "1.5" valem 1,5 e 0,5).
+// Valores negativos, NaN e infinitos são rejeitados.
+func ParseAmount(input string) (float64, error) {
+	s := strings.TrimSpace(input)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "R$"), "US$")
+	s = strings.TrimPrefix(s, "$")
+	s = strings.ReplaceAll(s, " ", "")
+
+	if s == "" {
+		return 0, fmt.Errorf("⚠️ Valor vazio")
+	}
+	if strings.HasPrefix(s, "-") {
+		return 0, fmt.Errorf("⚠️ O valor não pode ser negativo: %s", input)
+	}
+
+	lastDot := strings.LastIndex(s, ".")
+	lastComma := strings.LastIndex(s, ",")
+
+	ok := true
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		decimal, thousands := lastComma, byte('.')
+		if lastDot > lastComma {
+			decimal, thousands = lastDot, ','
+		}
+		var intPart string
+		intPart, ok = stripThousands(s[:decimal], thousands)
+		s = intPart + "." + s[decimal+1:]
+	case thousandsGroup.MatchString(s):
+		s = strings.NewReplacer(".", "", ",", "").Replace(s)
+	case strings.Count(s, ",") > 1:
+		s, ok = stripThousands(s, ',')
+	case lastComma >= 0:
+		s = strings.Replace(s, ",", ".", 1)
+	case strings.Count(s, ".") > 1:
+		s, ok = stripThousands(s, '.')
+	}
+
+	if !ok || !plainNumber.MatchString(s) {
+		return 0, fmt.Errorf("⚠️ Valor inválido: %s", input)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("⚠️ Valor inválido: %s", input)
+	}
+	return v, nil
+}
+
+// stripThousands remove o separador de milhar, exigindo grupos de três dígitos após o primeiro
+// (1.234.567 vale; 1.2.3 e 12.34.567 não)
+func stripThousands(s string, sep byte) (string, bool) {
+	groups := strings.Split(s, string(sep))
+	if len(groups[0]) == 0 || len(groups[0]) > 3 && len(groups) > 1 {
+		return s, false
+	}
+	for _, g := range groups[1:] {
+		if len(g) != 3 {
+			return s, false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
@@ -0,0 +1,60 @@
+package utils
+
+import "testing"
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"500", 500, false},
+		{"R$ 500", 500, false},
+		{"US$ 2500", 2500, false},
+		{"$10", 10, false},
+		{"0,05", 0.05, false},
+		{"0.05", 0.05, false},
+		{"1,5", 1.5, false},
+		{"1.5", 1.5, false},
+		{"1.500", 1500, false},
+		{"1,500", 1500, false},
+		{"600.000", 600000, false},
+		{"0.500", 0.5, false},
+		{"0,500", 0.5, false},
+		{"12.3456", 12.3456, false},
+		{"1.234,56", 1234.56, false},
+		{"1,234.56", 1234.56, false},
+		{"1.000.000", 1000000, false},
+		{"1,000,000", 1000000, false},
+		{"1.000.000,50", 1000000.5, false},
+		{".5", 0.5, false},
+		{"", 0, true},
+		{"abc", 0, true},
+		{"-5", 0, true},
+		{"inf", 0, true},
+		{"+Inf", 0, true},
+		{"nan", 0, true},
+		{"0x10", 0, true},
+		{"1e3", 0, true},
+		{"1.2.3", 0, true},
+		{"12.34.567", 0, true},
+		{"1,2,3", 0, true},
+		{"1.2.3,45", 0, true},
+		{"1234.567.890", 0, true},
+		{"1,234.5.6", 0, true},
+		{"1234,56", 1234.56, false},
+		{"1234.56", 1234.56, false},
+		{"12.345.678", 12345678, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAmount(%q) erro = %v, quero erro = %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %v, quero %v", tt.in, got, tt.want)
+		}
+	}
+}